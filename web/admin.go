@@ -0,0 +1,424 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// adminSecret est le secret partagé attendu dans l'en-tête X-Admin-Secret.
+// Si la variable d'environnement ADMIN_SECRET est vide, les routes d'administration sont désactivées.
+var adminSecret = os.Getenv("ADMIN_SECRET")
+
+// Taille maximum d'un fichier envoyé à /admin/words/import
+const maxWordImportSize = 1 << 20
+
+// Vérifie que la requête porte le secret d'administration
+func adminAuthorized(r *http.Request) bool {
+	if adminSecret == "" {
+		return false
+	}
+	provided := r.Header.Get("X-Admin-Secret")
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(adminSecret)) == 1
+}
+
+// Handler pour ajouter un mot à une liste sans redémarrer le serveur
+func addWordHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, msg(lang, "method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+	if !adminAuthorized(r) {
+		http.Error(w, msg(lang, "forbidden"), http.StatusForbidden)
+		return
+	}
+
+	category := r.FormValue("category")
+	difficulty := r.FormValue("difficulty")
+	// Même normalisation et mêmes règles que les listes de mots
+	word, ok := validWord(r.FormValue("word"), manager.config.FoldAccents)
+	if !ok {
+		http.Error(w, msg(lang, "word_letters_only"), http.StatusBadRequest)
+		return
+	}
+
+	manager.wordsMu.Lock()
+	defer manager.wordsMu.Unlock()
+
+	categoryWords, exists := manager.words[category]
+	if !exists {
+		http.Error(w, msg(lang, "unknown_category"), http.StatusBadRequest)
+		return
+	}
+	words, exists := categoryWords[difficulty]
+	if !exists {
+		http.Error(w, msg(lang, "unknown_difficulty"), http.StatusBadRequest)
+		return
+	}
+	for _, existing := range words {
+		if strings.ToLower(existing) == word {
+			http.Error(w, msg(lang, "word_exists"), http.StatusConflict)
+			return
+		}
+	}
+
+	// Ajouter le mot au fichier avant de mettre à jour la liste en mémoire
+	if err := appendWordToFile(wordFilePath(manager.config.WordsDir, category, difficulty), word); err != nil {
+		slog.Error("Erreur d'écriture dans le fichier de mots", "err", err)
+		http.Error(w, msg(lang, "word_save_failed"), http.StatusInternalServerError)
+		return
+	}
+	categoryWords[difficulty] = append(words, word)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"category":   category,
+		"difficulty": difficulty,
+		"word":       word,
+		"count":      len(categoryWords[difficulty]),
+	})
+}
+
+// Handler pour importer en une fois un fichier de mots (champ multipart
+// "file", un mot par ligne) dans une liste. Les lignes sont lues comme au
+// chargement des listes ; les mots déjà présents, dans la liste ou plus haut
+// dans le fichier, sont ignorés et comptés à part.
+func importWordsHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, msg(lang, "method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+	if !adminAuthorized(r) {
+		http.Error(w, msg(lang, "forbidden"), http.StatusForbidden)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxWordImportSize)
+	if err := r.ParseMultipartForm(maxWordImportSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, msg(lang, "import_too_large"), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, msg(lang, "import_invalid_form"), http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, msg(lang, "import_file_missing"), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, msg(lang, "import_read_failed"), http.StatusBadRequest)
+		return
+	}
+
+	category := r.FormValue("category")
+	difficulty := r.FormValue("difficulty")
+
+	manager.wordsMu.Lock()
+	defer manager.wordsMu.Unlock()
+
+	categoryWords, exists := manager.words[category]
+	if !exists {
+		http.Error(w, msg(lang, "unknown_category"), http.StatusBadRequest)
+		return
+	}
+	words, exists := categoryWords[difficulty]
+	if !exists {
+		http.Error(w, msg(lang, "unknown_difficulty"), http.StatusBadRequest)
+		return
+	}
+
+	known := make(map[string]bool, len(words))
+	for _, existing := range words {
+		known[strings.ToLower(existing)] = true
+	}
+	var added []string
+	skipped := 0
+	for _, word := range parseWordList(data, manager.config.FoldAccents) {
+		if known[word] {
+			skipped++
+			continue
+		}
+		known[word] = true
+		added = append(added, word)
+	}
+
+	// Ajouter les mots au fichier avant de mettre à jour la liste en mémoire
+	if len(added) > 0 {
+		if err := appendWordsToFile(wordFilePath(manager.config.WordsDir, category, difficulty), added); err != nil {
+			slog.Error("Erreur d'écriture dans le fichier de mots", "err", err)
+			http.Error(w, msg(lang, "words_save_failed"), http.StatusInternalServerError)
+			return
+		}
+		categoryWords[difficulty] = append(words, added...)
+	}
+	slog.Info("Mots importés", "event", "words_imported",
+		"category", category,
+		"difficulty", difficulty,
+		"added", len(added),
+		"skipped", skipped)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"category":   category,
+		"difficulty": difficulty,
+		"added":      len(added),
+		"skipped":    skipped,
+		"count":      len(categoryWords[difficulty]),
+	})
+}
+
+// wordListStat décrit le nombre de mots chargés pour une catégorie et une difficulté
+type wordListStat struct {
+	Category   string `json:"category"`
+	Difficulty string `json:"difficulty"`
+	Count      int    `json:"count"`
+	Empty      bool   `json:"empty"`
+}
+
+// Handler listant le nombre de mots chargés par catégorie et difficulté,
+// pour repérer les listes vides ou manquantes sans lire les logs
+func wordStatsHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodGet {
+		http.Error(w, msg(lang, "method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+	if !adminAuthorized(r) {
+		http.Error(w, msg(lang, "forbidden"), http.StatusForbidden)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, wordListStats())
+}
+
+// Handler pour relire les listes de mots depuis le dossier après une
+// modification des fichiers. Les parties en cours gardent leur mot : seules
+// les nouvelles parties utilisent les listes rechargées.
+func reloadWordsHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, msg(lang, "method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+	if !adminAuthorized(r) {
+		http.Error(w, msg(lang, "forbidden"), http.StatusForbidden)
+		return
+	}
+
+	words := loadWords(manager.config.WordsDir, manager.config.FoldAccents)
+	themed := loadThemedWords(manager.config.WordsDir, manager.config.FoldAccents)
+	// Des fichiers illisibles ou supprimés ne doivent pas vider les listes en service
+	if allWordListsEmpty(words) {
+		http.Error(w, msg(lang, "words_reload_empty"), http.StatusConflict)
+		return
+	}
+	manager.setWords(words, themed)
+	slog.Info("Listes de mots rechargées", "event", "words_reloaded", "words_dir", manager.config.WordsDir)
+
+	writeJSON(w, http.StatusOK, wordListStats())
+}
+
+// Retourne le nombre de mots chargés par liste, le total et le nombre de listes vides
+func wordListStats() map[string]interface{} {
+	stats := []wordListStat{}
+	total, empty := 0, 0
+
+	manager.wordsMu.RLock()
+	for _, category := range wordCategories {
+		for _, difficulty := range wordDifficulties {
+			count := len(manager.words[category][difficulty])
+			stats = append(stats, wordListStat{
+				Category:   category,
+				Difficulty: difficulty,
+				Count:      count,
+				Empty:      count == 0,
+			})
+			total += count
+			if count == 0 {
+				empty++
+			}
+		}
+	}
+	manager.wordsMu.RUnlock()
+
+	return map[string]interface{}{
+		"lists":       stats,
+		"total":       total,
+		"empty_lists": empty,
+	}
+}
+
+// Ajoute un mot en fin de fichier, sur sa propre ligne
+func appendWordToFile(filePath, word string) error {
+	return appendWordsToFile(filePath, []string{word})
+}
+
+// Ajoute des mots en fin de fichier, un par ligne
+func appendWordsToFile(filePath string, words []string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Garantir que le premier mot commence sur une nouvelle ligne
+	lines := strings.Join(words, "\n") + "\n"
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		lines = "\n" + lines
+	}
+	_, err = f.WriteString(lines)
+	return err
+}
+
+// Handler pour supprimer tous les scores d'un joueur, ainsi que son
+// classement, sa série de victoires et son niveau adaptatif
+func deleteScoresHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodDelete {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(lang, "method_not_allowed"))
+		return
+	}
+	if !adminAuthorized(r) {
+		writeAPIError(w, http.StatusForbidden, "forbidden", msg(lang, "forbidden"))
+		return
+	}
+
+	username := strings.TrimSpace(r.URL.Query().Get("username"))
+	if username == "" {
+		writeAPIError(w, http.StatusBadRequest, "username_required", msg(lang, "username_required"))
+		return
+	}
+
+	deleted, err := removePlayerScores(username)
+	if err != nil {
+		slog.Error("Erreur de suppression des scores", "username", username, "err", err)
+		writeAPIError(w, http.StatusInternalServerError, "scores_delete_failed", msg(lang, "scores_delete_failed"))
+		return
+	}
+	if err := removePlayerRating(username); err != nil {
+		slog.Error("Erreur de suppression du classement", "username", username, "err", err)
+	}
+	if err := removePlayerStreak(username); err != nil {
+		slog.Error("Erreur de suppression de la série", "username", username, "err", err)
+	}
+	if err := removePlayerProfile(username); err != nil {
+		slog.Error("Erreur de suppression du profil", "username", username, "err", err)
+	}
+
+	slog.Info("Scores supprimés", "event", "scores_deleted", "username", username, "count", deleted)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"username": username,
+		"deleted":  deleted,
+	})
+}
+
+// Réécrit le fichier des scores sans les lignes du joueur, via un fichier
+// temporaire renommé pour ne jamais laisser un fichier à moitié écrit.
+// Les lignes illisibles sont conservées telles quelles.
+// Retourne le nombre de scores supprimés.
+func removePlayerScores(username string) (int, error) {
+	scoresMutex.Lock()
+	defer scoresMutex.Unlock()
+
+	data, err := os.ReadFile(manager.config.ScoresPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var kept strings.Builder
+	deleted := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var score Score
+		if err := json.Unmarshal([]byte(line), &score); err == nil && score.Username == username {
+			deleted++
+			continue
+		}
+		kept.WriteString(line + "\n")
+	}
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(manager.config.ScoresPath), 0755); err != nil {
+		return 0, err
+	}
+	tmpPath := manager.config.ScoresPath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(kept.String()), 0644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmpPath, manager.config.ScoresPath); err != nil {
+		return 0, err
+	}
+	invalidateScoresCache()
+	return deleted, nil
+}
+
+// Retire le joueur du fichier des classements
+func removePlayerRating(username string) error {
+	ratingsMutex.Lock()
+	defer ratingsMutex.Unlock()
+
+	ratings, err := readRatings()
+	if err != nil {
+		return err
+	}
+	if _, exists := ratings[username]; !exists {
+		return nil
+	}
+	delete(ratings, username)
+	return writeRatings(ratings)
+}
+
+// Retire le joueur du fichier des séries
+func removePlayerStreak(username string) error {
+	streaksMutex.Lock()
+	defer streaksMutex.Unlock()
+
+	streaks, err := readStreaks()
+	if err != nil {
+		return err
+	}
+	if _, exists := streaks[username]; !exists {
+		return nil
+	}
+	delete(streaks, username)
+	return writeStreaks(streaks)
+}
+
+// Retire le joueur du fichier des profils
+func removePlayerProfile(username string) error {
+	profilesMutex.Lock()
+	defer profilesMutex.Unlock()
+
+	profiles, err := readProfiles()
+	if err != nil {
+		return err
+	}
+	if _, exists := profiles[username]; !exists {
+		return nil
+	}
+	delete(profiles, username)
+	return writeProfiles(profiles)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDeleteScoresRemovesPlayerFiles(t *testing.T) {
+	useTempScores(t)
+	saved := adminSecret
+	adminSecret = "secret"
+	t.Cleanup(func() { adminSecret = saved })
+
+	for _, username := range []string{"alice", "bob"} {
+		game := newGame(username, "easy", "animals", "", "chat")
+		game.Status = "won"
+		game.AdaptiveLevel = "easy"
+		saveScore(game)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/scores?username=alice", nil)
+	req.Header.Set("X-Admin-Secret", "secret")
+	rec := httptest.NewRecorder()
+	deleteScoresHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+
+	profiles, err := readProfiles()
+	if err != nil {
+		t.Fatalf("readProfiles: %v", err)
+	}
+	if _, exists := profiles["alice"]; exists {
+		t.Error("alice still has a profile")
+	}
+	if _, exists := profiles["bob"]; !exists {
+		t.Error("bob lost their profile")
+	}
+	streaks, err := readStreaks()
+	if err != nil {
+		t.Fatalf("readStreaks: %v", err)
+	}
+	if _, exists := streaks["alice"]; exists {
+		t.Error("alice still has a streak")
+	}
+}
+
+func TestAddWordValidation(t *testing.T) {
+	saved := adminSecret
+	adminSecret = "secret"
+	t.Cleanup(func() { adminSecret = saved })
+
+	for _, word := range []string{"-", " - ", "42", ""} {
+		form := url.Values{"category": {"animals"}, "difficulty": {"easy"}, "word": {word}}
+		req := httptest.NewRequest(http.MethodPost, "/admin/words", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Admin-Secret", "secret")
+		rec := httptest.NewRecorder()
+		addWordHandler(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("word %q: status %d, want 400", word, rec.Code)
+		}
+	}
+}
+
+func TestImportWordsErrorsTranslated(t *testing.T) {
+	saved := adminSecret
+	adminSecret = "secret"
+	t.Cleanup(func() { adminSecret = saved })
+
+	for lang, want := range map[string]string{"fr": "Formulaire multipart invalide.", "en": "Invalid multipart form."} {
+		req := httptest.NewRequest(http.MethodPost, "/admin/words/import?lang="+lang, strings.NewReader("pas un formulaire"))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+		req.Header.Set("X-Admin-Secret", "secret")
+		rec := httptest.NewRecorder()
+		importWordsHandler(rec, req)
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("lang %s: status %d, body %q, want 400 with %q", lang, rec.Code, rec.Body.String(), want)
+		}
+	}
+}
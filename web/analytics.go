@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// categoryResult compte les parties terminées d'une catégorie
+type categoryResult struct {
+	Played int `json:"played"`
+	Won    int `json:"won"`
+}
+
+// letterAnalytics accumule les lettres proposées par les joueurs
+type letterAnalytics struct {
+	Letters       map[string]int            `json:"letters"`        // Toutes les lettres proposées
+	FirstLetters  map[string]int            `json:"first_letters"`  // Première lettre proposée dans une partie
+	ByCategory    map[string]map[string]int `json:"by_category"`    // Lettres proposées par catégorie
+	CategoryGames map[string]categoryResult `json:"category_games"` // Parties jouées et gagnées par catégorie
+}
+
+var (
+	analytics      = newLetterAnalytics()
+	analyticsMutex sync.Mutex
+)
+
+// Chemin de la sauvegarde des statistiques de lettres, à côté des scores
+func analyticsStatePath() string {
+	return manager.config.scoresFile("letters.json")
+}
+
+func newLetterAnalytics() *letterAnalytics {
+	return &letterAnalytics{
+		Letters:       make(map[string]int),
+		FirstLetters:  make(map[string]int),
+		ByCategory:    make(map[string]map[string]int),
+		CategoryGames: make(map[string]categoryResult),
+	}
+}
+
+// Enregistre une lettre proposée par un joueur
+func recordLetterGuess(category, letter string, first bool) {
+	analyticsMutex.Lock()
+	defer analyticsMutex.Unlock()
+
+	analytics.Letters[letter]++
+	if first {
+		analytics.FirstLetters[letter]++
+	}
+	if analytics.ByCategory[category] == nil {
+		analytics.ByCategory[category] = make(map[string]int)
+	}
+	analytics.ByCategory[category][letter]++
+}
+
+// Enregistre le résultat d'une partie terminée
+func recordGameResult(category, status string) {
+	analyticsMutex.Lock()
+	defer analyticsMutex.Unlock()
+
+	result := analytics.CategoryGames[category]
+	result.Played++
+	if status == "won" {
+		result.Won++
+	}
+	analytics.CategoryGames[category] = result
+}
+
+// Handler pour consulter les statistiques de lettres en JSON
+func letterAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(lang, "method_not_allowed"))
+		return
+	}
+
+	analyticsMutex.Lock()
+	data, err := json.Marshal(analytics)
+	analyticsMutex.Unlock()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "analytics_unavailable", msg(lang, "analytics_unavailable"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// attemptsBucket compte les victoires obtenues avec un nombre donné de
+// mauvaises propositions
+type attemptsBucket struct {
+	WrongGuesses int `json:"wrong_guesses"`
+	Wins         int `json:"wins"`
+}
+
+// attemptsDistribution est la réponse de GET /api/analytics/attempts
+type attemptsDistribution struct {
+	Category   string           `json:"category,omitempty"`
+	Difficulty string           `json:"difficulty,omitempty"`
+	Wins       int              `json:"wins"`
+	Average    float64          `json:"average"`
+	Buckets    []attemptsBucket `json:"buckets"` // De 0 au maximum observé, sans trou
+}
+
+// Handler renvoyant l'histogramme du nombre de mauvaises propositions des
+// victoires enregistrées, avec les mêmes filtres que /api/scores. Les scores
+// sans historique des propositions (lignes anciennes) sont ignorés : leur
+// nombre d'erreurs est inconnu.
+func attemptsAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(lang, "method_not_allowed"))
+		return
+	}
+
+	filters, err := parseScoreFilters(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errorCode(err), errorText(lang, err))
+		return
+	}
+	filters.Status = "won"
+	scores, err := loadScores(filters)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "scores_unavailable", msg(lang, "scores_unavailable"))
+		return
+	}
+
+	distribution := attemptsDistribution{
+		Category:   filters.Category,
+		Difficulty: filters.Difficulty,
+		Buckets:    []attemptsBucket{},
+	}
+	total := 0
+	for _, score := range scores {
+		if len(score.Guesses) == 0 || score.WrongGuesses < 0 {
+			continue
+		}
+		for len(distribution.Buckets) <= score.WrongGuesses {
+			distribution.Buckets = append(distribution.Buckets, attemptsBucket{WrongGuesses: len(distribution.Buckets)})
+		}
+		distribution.Buckets[score.WrongGuesses].Wins++
+		distribution.Wins++
+		total += score.WrongGuesses
+	}
+	if distribution.Wins > 0 {
+		distribution.Average = float64(total) / float64(distribution.Wins)
+	}
+
+	writeJSON(w, http.StatusOK, distribution)
+}
+
+// Sauvegarde les compteurs sur disque
+func persistAnalytics() {
+	analyticsMutex.Lock()
+	data, err := json.Marshal(analytics)
+	analyticsMutex.Unlock()
+	if err != nil {
+		slog.Error("Erreur de marshalling des statistiques de lettres", "err", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(analyticsStatePath()), 0755); err != nil {
+		slog.Error("Erreur de création du dossier des statistiques", "err", err)
+		return
+	}
+
+	tmpPath := analyticsStatePath() + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		slog.Error("Erreur d'écriture des statistiques de lettres", "err", err)
+		return
+	}
+	if err := os.Rename(tmpPath, analyticsStatePath()); err != nil {
+		slog.Error("Erreur de renommage des statistiques de lettres", "err", err)
+	}
+}
+
+// Restaure les compteurs sauvegardés
+func loadAnalytics() {
+	data, err := os.ReadFile(analyticsStatePath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Error("Erreur de lecture des statistiques de lettres", "err", err)
+		}
+		return
+	}
+
+	saved := newLetterAnalytics()
+	if err := json.Unmarshal(data, saved); err != nil {
+		slog.Error("Erreur de parsing des statistiques de lettres", "err", err)
+		return
+	}
+
+	analyticsMutex.Lock()
+	analytics = saved
+	analyticsMutex.Unlock()
+}
@@ -0,0 +1,560 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiStartRequest représente le corps JSON attendu par POST /api/game
+type apiStartRequest struct {
+	Username          string `json:"username"`
+	Difficulty        string `json:"difficulty"`
+	Category          string `json:"category"`
+	Theme             string `json:"theme"`
+	Hints             *int   `json:"hints,omitempty"`
+	HintPolicy        string `json:"hint_policy,omitempty"`
+	HintCost          *int   `json:"hint_cost,omitempty"`
+	HintStrategy      string `json:"hint_strategy,omitempty"`
+	Avatar            string `json:"avatar,omitempty"`
+	Biased            bool   `json:"biased,omitempty"`
+	ListID            string `json:"list_id,omitempty"`
+	Lang              string `json:"lang,omitempty"`
+	Hardcore          bool   `json:"hardcore,omitempty"`
+	WordOnly          bool   `json:"word_only,omitempty"`
+	Casual            bool   `json:"casual,omitempty"`
+	AccentInsensitive bool   `json:"accent_insensitive,omitempty"`
+	Practice          bool   `json:"practice,omitempty"`
+	ThemeClue         bool   `json:"theme_clue,omitempty"`
+}
+
+// apiGuessRequest représente le corps JSON attendu par POST /api/game/guess
+type apiGuessRequest struct {
+	SessionID string `json:"session_id"`
+	GameID    string `json:"game_id,omitempty"`
+	CSRFToken string `json:"csrf_token"`
+	Guess     string `json:"guess"`
+}
+
+// apiGameState est la vue JSON d'une partie renvoyée aux clients de l'API.
+// Le mot n'est renseigné qu'une fois la partie terminée.
+type apiGameState struct {
+	SessionID         string            `json:"session_id,omitempty"`
+	GameID            string            `json:"game_id"`
+	CSRFToken         string            `json:"csrf_token,omitempty"`
+	ResumeCode        string            `json:"resume_code,omitempty"`
+	Username          string            `json:"username"`
+	Avatar            string            `json:"avatar"`
+	Difficulty        string            `json:"difficulty"`
+	AdaptiveLevel     string            `json:"adaptive_level,omitempty"`
+	Category          string            `json:"category"`
+	Theme             string            `json:"theme"`
+	WordTheme         string            `json:"word_theme,omitempty"`
+	WordOnly          bool              `json:"word_only,omitempty"`
+	Practice          bool              `json:"practice,omitempty"`
+	AccentInsensitive bool              `json:"accent_insensitive,omitempty"`
+	WrongGuesses      int               `json:"wrong_guesses"`
+	MaskedWord        string            `json:"masked_word"`
+	Word              string            `json:"word,omitempty"`
+	WordIndex         int               `json:"word_index,omitempty"`
+	GuessedLetters    []string          `json:"guessed_letters"`
+	LetterStates      map[string]string `json:"letter_states"`
+	WrongLetters      []string          `json:"wrong_letters"`
+	AttemptsLeft      int               `json:"attempts_left"`
+	MaxAttempts       int               `json:"max_attempts"`
+	HintsUsed         int               `json:"hints_used"`
+	MaxHints          int               `json:"max_hints"`
+	HintsRemaining    int               `json:"hints_remaining"`
+	FreezesRemaining  int               `json:"freezes_remaining"`
+	HintPolicy        string            `json:"hint_policy"`
+	HintCost          int               `json:"hint_cost"`
+	HintStrategy      string            `json:"hint_strategy"`
+	HintClue          string            `json:"hint_clue,omitempty"`
+	ThemeClue         string            `json:"theme_clue,omitempty"`
+	Status            string            `json:"status"`
+	DurationSeconds   int               `json:"duration_seconds,omitempty"`
+	SecondsLeft       int               `json:"seconds_left,omitempty"`
+	Deadline          *time.Time        `json:"deadline,omitempty"`
+	Message           string            `json:"message,omitempty"`
+	MessageType       string            `json:"message_type,omitempty"`
+}
+
+// Construit la vue JSON d'une partie sans révéler le mot tant qu'elle est en cours
+func newAPIGameState(game *Game) apiGameState {
+	state := apiGameState{
+		GameID:            game.ID,
+		Username:          game.Username,
+		Avatar:            game.Avatar,
+		Difficulty:        game.Difficulty,
+		AdaptiveLevel:     game.AdaptiveLevel,
+		Category:          game.Category,
+		Theme:             game.Theme,
+		WordTheme:         game.WordTheme,
+		WordOnly:          game.WordOnlyMode,
+		Practice:          game.Practice,
+		AccentInsensitive: game.AccentInsensitive,
+		WrongGuesses:      game.WrongGuesses,
+		MaskedWord:        game.MaskedWord(),
+		GuessedLetters:    game.GuessedLetters,
+		LetterStates:      letterStates(game),
+		WrongLetters:      wrongGuesses(game),
+		AttemptsLeft:      game.AttemptsLeft,
+		MaxAttempts:       game.MaxAttempts,
+		HintsUsed:         game.HintsUsed,
+		MaxHints:          game.MaxHints,
+		HintsRemaining:    game.HintsRemaining(),
+		FreezesRemaining:  game.FreezesRemaining(),
+		HintPolicy:        game.HintPolicy,
+		HintCost:          game.HintAttempts(),
+		HintStrategy:      game.HintStrategy,
+		HintClue:          game.HintClue,
+		ThemeClue:         game.ThemeClue(),
+		Status:            game.Status,
+		DurationSeconds:   game.DurationSeconds,
+		Message:           game.Message,
+		MessageType:       game.MessageType,
+	}
+	// Une partie sans limite de temps (entraînement) n'a ni heure limite ni compte à rebours
+	if !game.Deadline.IsZero() {
+		deadline := game.Deadline
+		state.Deadline = &deadline
+		state.SecondsLeft = game.SecondsLeft()
+	}
+	// La position du mot dans sa liste permettrait de le retrouver : elle
+	// n'est donnée qu'avec le mot, une fois la partie terminée
+	if game.Status != "ongoing" {
+		state.Word = game.Word
+		state.WordIndex = game.WordIndex
+	}
+	return state
+}
+
+// Handler pour démarrer une partie via l'API JSON
+func apiStartHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodPost {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(lang, "method_not_allowed"))
+		return
+	}
+
+	var req apiStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_json", msg(lang, "invalid_json"))
+		return
+	}
+	if _, ok := catalogs[req.Lang]; ok {
+		lang = req.Lang
+	}
+
+	username := strings.TrimSpace(req.Username)
+	if username == "" || req.Difficulty == "" || req.Category == "" || req.Theme == "" {
+		writeAPIError(w, http.StatusBadRequest, "required_fields", msg(lang, "required_fields"))
+		return
+	}
+
+	username, err := sanitizeUsername(username)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errorCode(err), errorText(lang, err))
+		return
+	}
+
+	hintsValue := ""
+	if req.Hints != nil {
+		hintsValue = strconv.Itoa(*req.Hints)
+	}
+	difficulty, adaptive := resolveDifficulty(username, req.Difficulty)
+	hints, err := parseMaxHints(hintsValue, difficulty)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errorCode(err), errorText(lang, err))
+		return
+	}
+
+	hintCostValue := ""
+	if req.HintCost != nil {
+		hintCostValue = strconv.Itoa(*req.HintCost)
+	}
+	hintCost, err := parseHintCost(hintCostValue, difficulty)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errorCode(err), errorText(lang, err))
+		return
+	}
+
+	hintPolicy, err := parseHintPolicy(req.HintPolicy)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errorCode(err), errorText(lang, err))
+		return
+	}
+
+	hintStrategy, err := parseHintStrategy(req.HintStrategy)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errorCode(err), errorText(lang, err))
+		return
+	}
+
+	avatar, err := parseAvatar(req.Avatar)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errorCode(err), errorText(lang, err))
+		return
+	}
+
+	word, source, wordTheme, err := getFreshWord(username, difficulty, req.Category, req.ListID, req.Biased, manager.config.RecentWords)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, errorCode(err), errorText(lang, err))
+		return
+	}
+
+	game := newGame(username, difficulty, req.Category, req.Theme, word)
+	game.MaxHints = hints
+	game.HintCost = hintCost
+	game.HintPolicy = hintPolicy
+	game.HintStrategy = hintStrategy
+	game.Avatar = avatar
+	game.BiasedWord = req.Biased
+	game.SourceCategory = source
+	game.WordTheme = wordTheme
+	game.WordIndex = manager.wordIndex(source, difficulty, game.WordTheme, word)
+	if adaptive {
+		game.AdaptiveLevel = difficulty
+	}
+	if req.Hardcore {
+		makeHardcore(game)
+	}
+	if req.WordOnly {
+		makeWordOnly(game)
+	}
+	game.CasualMode = req.Casual
+	if req.AccentInsensitive || req.Casual {
+		enableAccentInsensitive(game)
+	}
+	if req.Practice {
+		makePractice(game)
+	}
+	if req.ThemeClue {
+		enableThemeClue(game)
+	}
+	game.Lang = lang
+	// Une nouvelle partie s'ajoute à la session existante, le cas échéant
+	sessionID := getSessionID(r)
+	if sessionID == "" {
+		sessionID = generateSessionID()
+	}
+	manager.NewGame(sessionID, game)
+
+	// La partie est publiée : une requête de la même session peut déjà la
+	// modifier, la réponse est donc construite à partir d'une copie
+	view := manager.Snapshot(game)
+	state := newAPIGameState(&view)
+	state.SessionID = sessionID
+	state.CSRFToken = view.CSRFToken
+	state.ResumeCode = view.ResumeCode
+	writeJSON(w, http.StatusCreated, state)
+}
+
+// Handler pour soumettre une lettre ou un mot via l'API JSON
+func apiGuessHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodPost {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(lang, "method_not_allowed"))
+		return
+	}
+
+	var req apiGuessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_json", msg(lang, "invalid_json"))
+		return
+	}
+
+	// L'ID de session peut venir du corps ou du cookie
+	sessionID := req.SessionID
+	if sessionID == "" {
+		sessionID = getSessionID(r)
+	}
+
+	game, exists := manager.Get(sessionID, req.GameID)
+	if !exists {
+		writeAPIError(w, http.StatusNotFound, "game_not_found", msg(lang, "game_not_found"))
+		return
+	}
+
+	var status int
+	view := manager.Update(game, func(game *Game) {
+		status = apiGuess(game, req)
+	})
+
+	if status == http.StatusForbidden {
+		writeAPIError(w, http.StatusForbidden, "invalid_csrf", msg(lang, "invalid_csrf"))
+		return
+	}
+	writeJSON(w, status, newAPIGameState(&view))
+}
+
+// Applique une proposition reçue par l'API et retourne le code HTTP de la
+// réponse.
+// Doit être appelée avec le verrou des parties.
+func apiGuess(game *Game, req apiGuessRequest) int {
+	// Vérifier le token CSRF
+	if req.CSRFToken != game.CSRFToken {
+		return http.StatusForbidden
+	}
+	// Une proposition arrivée après une trop longue inactivité ou après
+	// l'heure limite fait perdre la partie
+	checkIdle(game, manager.config.IdleTimeout)
+	previousActivity := game.LastActivity
+	game.LastActivity = time.Now()
+	checkDeadline(game)
+
+	if game.Status != "ongoing" {
+		return http.StatusConflict
+	}
+
+	if guessTooFast(game, previousActivity, game.LastActivity, manager.config.MinGuessInterval) {
+		return http.StatusTooManyRequests
+	}
+
+	guess := strings.TrimSpace(strings.ToLower(req.Guess))
+	if !applyGuess(game, guess) {
+		return http.StatusBadRequest
+	}
+	return http.StatusOK
+}
+
+// Handler pour consulter l'état d'une partie sans jouer, via le cookie de session.
+// Le mot n'est jamais renvoyé tant que la partie est en cours.
+func apiStateHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(lang, "method_not_allowed"))
+		return
+	}
+
+	game, exists := manager.Get(getSessionID(r), r.URL.Query().Get("game"))
+	if !exists {
+		writeAPIError(w, http.StatusNotFound, "game_not_found", msg(lang, "game_not_found"))
+		return
+	}
+
+	// Une partie dont l'heure limite est dépassée, ou restée trop longtemps
+	// sans proposition, est perdue
+	view := manager.Update(game, func(game *Game) {
+		checkDeadline(game)
+		checkIdle(game, manager.config.IdleTimeout)
+	})
+
+	writeJSON(w, http.StatusOK, newAPIGameState(&view))
+}
+
+// Handler du leaderboard JSON : GET liste les scores, DELETE supprime ceux
+// d'un joueur (administration)
+func apiScoresHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		apiListScoresHandler(w, r)
+	case http.MethodDelete:
+		deleteScoresHandler(w, r)
+	default:
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(requestLang(w, r), "method_not_allowed"))
+	}
+}
+
+// Renvoie la page demandée des scores, avec les mêmes filtres, tri et
+// pagination que la page /scores. Le nombre total de scores correspondant aux
+// filtres est donné dans l'en-tête X-Total-Count.
+func apiListScoresHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	filters, err := parseScoreFilters(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, errorCode(err), errorText(lang, err))
+		return
+	}
+	scores, err := loadScores(filters)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "scores_unavailable", msg(lang, "scores_unavailable"))
+		return
+	}
+
+	page, size := parsePagination(r)
+	pageScores := paginateScores(scores, page, size)
+	if pageScores == nil {
+		pageScores = []Score{}
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(scores)))
+	writeJSON(w, http.StatusOK, pageScores)
+}
+
+// Handler de GET /api/scores/{id} : renvoie le détail d'une partie terminée,
+// avec l'historique de ses propositions, ou 404 si l'identifiant est inconnu
+func apiScoreHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(lang, "method_not_allowed"))
+		return
+	}
+
+	id := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/api/scores/"))
+	score, found, err := findScore(id)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "scores_unavailable", msg(lang, "scores_unavailable"))
+		return
+	}
+	if id == "" || !found {
+		writeAPIError(w, http.StatusNotFound, "score_not_found", msg(lang, "score_not_found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, score)
+}
+
+// apiCSRFResponse est la réponse de GET /api/csrf
+type apiCSRFResponse struct {
+	GameID    string `json:"game_id"`
+	CSRFToken string `json:"csrf_token"`
+}
+
+// Handler qui renouvelle le token CSRF d'une partie de la session et renvoie
+// le nouveau. L'ancien token est invalidé immédiatement : un formulaire
+// affiché avant le renouvellement sera refusé (403) et doit être rechargé,
+// les clients de l'API doivent utiliser le token renvoyé pour la suite.
+func apiCSRFHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(lang, "method_not_allowed"))
+		return
+	}
+
+	game, exists := manager.Get(getSessionID(r), r.URL.Query().Get("game"))
+	if !exists {
+		writeAPIError(w, http.StatusNotFound, "game_not_found", msg(lang, "game_not_found"))
+		return
+	}
+
+	view := manager.Update(game, func(game *Game) {
+		game.CSRFToken = generateCSRFToken()
+	})
+	token := view.CSRFToken
+
+	// Un token ne doit jamais être servi depuis un cache
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, apiCSRFResponse{GameID: game.ID, CSRFToken: token})
+}
+
+// apiUsernameCheck est la réponse de GET /api/username/check
+type apiUsernameCheck struct {
+	Name   string `json:"name"`             // Pseudo tel qu'il serait enregistré
+	Valid  bool   `json:"valid"`            // Le pseudo respecte les règles de saisie
+	Reason string `json:"reason,omitempty"` // Raison du refus, dans la langue du joueur
+	Exists bool   `json:"exists"`           // Un score existe déjà sous ce pseudo
+}
+
+// Handler vérifiant qu'un pseudo est valide et s'il a déjà des scores, pour
+// que l'interface réagisse avant la création de la partie. Le pseudo n'est
+// pas réservé.
+func apiUsernameCheckHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(lang, "method_not_allowed"))
+		return
+	}
+
+	name, err := sanitizeUsername(r.URL.Query().Get("name"))
+	if err != nil {
+		writeJSON(w, http.StatusOK, apiUsernameCheck{Reason: errorText(lang, err)})
+		return
+	}
+
+	scores, err := readScores()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "scores_unavailable", msg(lang, "scores_unavailable"))
+		return
+	}
+	check := apiUsernameCheck{Name: name, Valid: true}
+	for _, score := range scores {
+		if strings.EqualFold(score.Username, name) {
+			check.Exists = true
+			break
+		}
+	}
+	writeJSON(w, http.StatusOK, check)
+}
+
+// apiCategoryMeta décrit une catégorie jouable et ses difficultés disponibles
+type apiCategoryMeta struct {
+	Name         string   `json:"name"`
+	Difficulties []string `json:"difficulties"`
+}
+
+// apiMeta est la réponse de GET /api/meta
+type apiMeta struct {
+	Categories   []apiCategoryMeta `json:"categories"`
+	Difficulties []string          `json:"difficulties"`
+}
+
+// Handler listant les catégories et difficultés qui ont au moins un mot,
+// pour que l'interface ne propose que des combinaisons jouables
+func apiMetaHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", msg(requestLang(w, r), "method_not_allowed"))
+		return
+	}
+
+	meta := apiMeta{Categories: []apiCategoryMeta{}, Difficulties: []string{}}
+	playable := make(map[string]bool) // Difficultés jouables dans au moins une catégorie
+
+	manager.wordsMu.RLock()
+	for _, category := range wordCategories {
+		if category == randomCategory {
+			continue
+		}
+		categoryMeta := apiCategoryMeta{Name: category}
+		for _, difficulty := range wordDifficulties {
+			if len(manager.words[category][difficulty]) > 0 {
+				categoryMeta.Difficulties = append(categoryMeta.Difficulties, difficulty)
+				playable[difficulty] = true
+			}
+		}
+		if len(categoryMeta.Difficulties) > 0 {
+			meta.Categories = append(meta.Categories, categoryMeta)
+		}
+	}
+	manager.wordsMu.RUnlock()
+
+	for _, difficulty := range wordDifficulties {
+		if playable[difficulty] {
+			meta.Difficulties = append(meta.Difficulties, difficulty)
+		}
+	}
+
+	// La catégorie "random" mélange toutes les autres : elle est jouable dans
+	// toutes les difficultés qui ont des mots quelque part
+	if len(meta.Difficulties) > 0 {
+		meta.Categories = append(meta.Categories, apiCategoryMeta{
+			Name:         randomCategory,
+			Difficulties: meta.Difficulties,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, meta)
+}
+
+// apiError est le corps JSON des réponses d'erreur de l'API : un code stable
+// pour les programmes et un message lisible dans la langue du joueur
+type apiError struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// Écrit une erreur JSON avec le code de statut donné
+func writeAPIError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, apiError{Error: code, Message: message})
+}
+
+// Écrit une réponse JSON avec le code de statut donné
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Erreur d'encodage de la réponse JSON", "err", err)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPICSRFRotation(t *testing.T) {
+	useTempScores(t)
+	sessionID := generateSessionID()
+	game := newGame("alice", "easy", "random", "", "banane")
+	game.Practice = true
+	gameID := manager.NewGame(sessionID, game)
+	oldToken := manager.Snapshot(game).CSRFToken
+
+	req := httptest.NewRequest(http.MethodGet, "/api/csrf?game="+gameID, nil)
+	req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+	rec := httptest.NewRecorder()
+	apiCSRFHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/csrf: status %d, want 200", rec.Code)
+	}
+	var rotated apiCSRFResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &rotated); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+	}
+	if rotated.CSRFToken == "" || rotated.CSRFToken == oldToken {
+		t.Fatalf("csrf_token = %q, want a new token", rotated.CSRFToken)
+	}
+
+	guess := func(token, letter string) int {
+		body, _ := json.Marshal(apiGuessRequest{SessionID: sessionID, GameID: gameID, CSRFToken: token, Guess: letter})
+		req := httptest.NewRequest(http.MethodPost, "/api/game/guess", strings.NewReader(string(body)))
+		rec := httptest.NewRecorder()
+		apiGuessHandler(rec, req)
+		return rec.Code
+	}
+	if code := guess(oldToken, "a"); code != http.StatusForbidden {
+		t.Errorf("guess with the old token: status %d, want 403", code)
+	}
+	if code := guess(rotated.CSRFToken, "a"); code != http.StatusOK {
+		t.Errorf("guess with the new token: status %d, want 200", code)
+	}
+	if view := manager.Snapshot(game); len(view.GuessedLetters) != 1 {
+		t.Errorf("GuessedLetters = %v, want only the guess made with the new token", view.GuessedLetters)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// archiveDateFormat est le format des dates utilisées comme clés de l'archive
+const archiveDateFormat = "2006-01-02"
+
+var (
+	dailyArchiveMutex sync.Mutex // Mutex pour sérialiser l'accès au fichier de l'archive
+)
+
+// Chemin de l'archive des mots du jour déjà tirés, par date puis par
+// catégorie, à côté des scores
+func dailyArchivePath() string {
+	return manager.config.scoresFile("daily.json")
+}
+
+// archiveSolver est un joueur ayant trouvé le mot du jour
+type archiveSolver struct {
+	Username string
+	Attempts int // Nombre de propositions, indices non compris
+}
+
+// archiveWord est un mot du jour passé, affiché sur la page /archive
+type archiveWord struct {
+	Category string
+	Word     string
+	Solvers  []archiveSolver
+}
+
+// archiveDay regroupe les mots du jour d'une date passée
+type archiveDay struct {
+	Date  string // Date affichée (JJ/MM/AAAA)
+	Words []archiveWord
+}
+
+// Lit l'archive des mots du jour. Un fichier absent donne une archive vide.
+// Doit être appelée avec dailyArchiveMutex verrouillé.
+func readDailyArchive() (map[string]map[string]string, error) {
+	archive := make(map[string]map[string]string)
+	data, err := os.ReadFile(dailyArchivePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return archive, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// Écrit l'archive dans un fichier temporaire puis le renomme.
+// Doit être appelée avec dailyArchiveMutex verrouillé.
+func writeDailyArchive(archive map[string]map[string]string) error {
+	data, err := json.Marshal(archive)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dailyArchivePath()), 0755); err != nil {
+		return err
+	}
+	tmpPath := dailyArchivePath() + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, dailyArchivePath())
+}
+
+// Retourne le mot du jour déjà enregistré pour la date et la catégorie
+func archivedDailyWord(date time.Time, category string) (string, bool) {
+	dailyArchiveMutex.Lock()
+	defer dailyArchiveMutex.Unlock()
+
+	archive, err := readDailyArchive()
+	if err != nil {
+		slog.Error("Erreur de lecture de l'archive des mots du jour", "err", err)
+		return "", false
+	}
+	word, exists := archive[date.Format(archiveDateFormat)][category]
+	return word, exists
+}
+
+// Enregistre le mot du jour d'une catégorie la première fois qu'il est tiré.
+// Retourne le mot enregistré pour la date, qui reste celui du premier tirage
+// si deux requêtes tirent le mot en même temps.
+func recordDailyWord(date time.Time, category, word string) string {
+	dailyArchiveMutex.Lock()
+	defer dailyArchiveMutex.Unlock()
+
+	archive, err := readDailyArchive()
+	if err != nil {
+		slog.Error("Erreur de lecture de l'archive des mots du jour", "err", err)
+		return word
+	}
+
+	day := date.Format(archiveDateFormat)
+	if archive[day] == nil {
+		archive[day] = make(map[string]string)
+	}
+	if existing, exists := archive[day][category]; exists {
+		return existing
+	}
+	archive[day][category] = word
+
+	if err := writeDailyArchive(archive); err != nil {
+		slog.Error("Erreur d'écriture de l'archive des mots du jour", "err", err)
+	}
+	return word
+}
+
+// Compte les propositions du joueur, sans les lettres révélées par un indice
+// ni les annulations
+func countAttempts(score Score) int {
+	attempts := 0
+	for _, event := range score.Guesses {
+		if !event.Hint && !event.Undo {
+			attempts++
+		}
+	}
+	return attempts
+}
+
+// Construit l'archive des jours passés, du plus récent au plus ancien, avec
+// les joueurs ayant trouvé chaque mot. Le jour en cours n'y figure pas pour
+// ne pas révéler les mots encore en jeu.
+func buildArchive(archive map[string]map[string]string, scores []Score, now time.Time) []archiveDay {
+	today := now.Format(archiveDateFormat)
+
+	// Joueurs ayant trouvé le mot du jour, par date puis par catégorie
+	solvers := make(map[string]map[string][]archiveSolver)
+	for _, score := range scores {
+		if score.Mode != "daily" || score.Status != "won" {
+			continue
+		}
+		day := time.Unix(score.Timestamp, 0).Format(archiveDateFormat)
+		if solvers[day] == nil {
+			solvers[day] = make(map[string][]archiveSolver)
+		}
+		solvers[day][score.Category] = append(solvers[day][score.Category], archiveSolver{
+			Username: score.Username,
+			Attempts: countAttempts(score),
+		})
+	}
+
+	var dates []string
+	for day := range archive {
+		if day < today {
+			dates = append(dates, day)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+
+	days := make([]archiveDay, 0, len(dates))
+	for _, day := range dates {
+		var categories []string
+		for category := range archive[day] {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+
+		display := day
+		if t, err := time.Parse(archiveDateFormat, day); err == nil {
+			display = t.Format("02/01/2006")
+		}
+		entry := archiveDay{Date: display}
+		for _, category := range categories {
+			daySolvers := solvers[day][category]
+			sort.Slice(daySolvers, func(i, j int) bool {
+				return daySolvers[i].Attempts < daySolvers[j].Attempts
+			})
+			entry.Words = append(entry.Words, archiveWord{
+				Category: category,
+				Word:     archive[day][category],
+				Solvers:  daySolvers,
+			})
+		}
+		days = append(days, entry)
+	}
+	return days
+}
+
+// Handler pour la page des mots du jour passés
+func archiveHandler(w http.ResponseWriter, r *http.Request) {
+	dailyArchiveMutex.Lock()
+	archive, err := readDailyArchive()
+	dailyArchiveMutex.Unlock()
+	if err != nil {
+		http.Error(w, "Impossible de lire l'archive des mots du jour.", http.StatusInternalServerError)
+		return
+	}
+
+	scores, err := readScores()
+	if err != nil {
+		http.Error(w, "Impossible de lire les scores.", http.StatusInternalServerError)
+		return
+	}
+
+	err = renderTemplate(w, "archive.html", buildArchive(archive, scores, time.Now()))
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestDailyWordStableAfterListChange(t *testing.T) {
+	useTempScores(t)
+	m := newGameManager(map[string]map[string][]string{
+		"animals": {dailyDifficulty: {"chat", "chien", "lapin"}},
+	}, nil, defaultConfig())
+
+	first, err := m.DailyWord("animals")
+	if err != nil {
+		t.Fatalf("DailyWord: %v", err)
+	}
+
+	// Une liste rechargée avec d'autres mots ne change pas le mot du jour
+	m.setWords(map[string]map[string][]string{
+		"animals": {dailyDifficulty: {"zebre", "tigre", "ours", "loup", "renard"}},
+	}, nil)
+	again, err := m.DailyWord("animals")
+	if err != nil {
+		t.Fatalf("DailyWord after reload: %v", err)
+	}
+	if again != first {
+		t.Errorf("DailyWord after reload = %q, want %q", again, first)
+	}
+
+	if _, err := m.DailyWord("countries"); err != errNoDailyWord {
+		t.Errorf("DailyWord(countries) error = %v, want errNoDailyWord", err)
+	}
+}
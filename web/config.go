@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Config regroupe les réglages du serveur modifiables par variables
+// d'environnement. Elle est chargée par main et confiée au GameManager : les
+// handlers lisent manager.config et passent les réglages utiles aux fonctions
+// qu'ils appellent.
+type Config struct {
+	Port       string        // PORT : port d'écoute du serveur HTTP
+	ScoresPath string        // SCORES_PATH : fichier des scores (classements et séries à côté)
+	WordsDir   string        // WORDS_DIR : dossier contenant les listes de mots
+	SessionTTL time.Duration // SESSION_TTL : durée d'inactivité avant expiration d'une partie
+	MaxHints   int           // MAX_HINTS : nombre d'indices par défaut
+
+	// MIN_GUESS_INTERVAL : délai minimum entre deux propositions en défi du
+	// jour et en course (ex : 200ms). Zéro, la valeur par défaut, le désactive.
+	MinGuessInterval time.Duration
+
+	// IDLE_TIMEOUT : délai sans proposition après lequel une partie
+	// chronométrée est abandonnée d'office (ex : 60s). Zéro, la valeur par
+	// défaut, le désactive.
+	IdleTimeout time.Duration
+
+	// WEBHOOK_URL : URL appelée en POST à chaque victoire (désactivé si vide)
+	WebhookURL string
+
+	// FOLD_ACCENTS : retirer les accents des mots au chargement des listes
+	// (désactivé par défaut)
+	FoldAccents bool
+
+	// FALLBACK_WORDS : utiliser les listes embarquées si aucun fichier de mots
+	// n'est disponible (activé par défaut). Désactivé, le serveur refuse de démarrer.
+	FallbackWords bool
+
+	// RECENT_WORDS : nombre de derniers mots servis à un joueur que le tirage
+	// évite de lui redonner (0 pour désactiver)
+	RecentWords int
+
+	// TRUST_PROXY : identifier le client par l'en-tête X-Forwarded-For pour la
+	// limite de création de parties. À n'activer que derrière un proxy de
+	// confiance, qui écrase cet en-tête (désactivé par défaut).
+	TrustProxy bool
+
+	// DEV : lire templates et fichiers statiques sur le disque plutôt que
+	// ceux embarqués, et relire les templates à chaque requête (désactivé par défaut)
+	Dev bool
+}
+
+// Retourne la configuration par défaut, identique au comportement historique
+func defaultConfig() Config {
+	return Config{
+		Port:       "8080",
+		ScoresPath: "scores/scores.json",
+		WordsDir:   "words",
+		SessionTTL: 30 * time.Minute,
+		MaxHints:   2,
+
+		FallbackWords: true,
+		RecentWords:   5,
+	}
+}
+
+// Construit la configuration à partir des variables d'environnement lues par
+// getenv (os.Getenv en production). Une variable absente garde sa valeur par
+// défaut, une valeur invalide est une erreur.
+func loadConfig(getenv func(string) string) (Config, error) {
+	cfg := defaultConfig()
+
+	if value := getenv("PORT"); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			return cfg, fmt.Errorf("PORT invalide : %q", value)
+		}
+		cfg.Port = value
+	}
+	if value := getenv("SCORES_PATH"); value != "" {
+		cfg.ScoresPath = value
+	}
+	if value := getenv("WORDS_DIR"); value != "" {
+		cfg.WordsDir = value
+	}
+	if value := getenv("SESSION_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return cfg, fmt.Errorf("SESSION_TTL invalide : %q (exemple : 30m)", value)
+		}
+		cfg.SessionTTL = ttl
+	}
+	if value := getenv("MAX_HINTS"); value != "" {
+		hints, err := strconv.Atoi(value)
+		if err != nil || hints < 0 || hints > maxHintsLimit {
+			return cfg, fmt.Errorf("MAX_HINTS invalide : %q (entre 0 et %d)", value, maxHintsLimit)
+		}
+		cfg.MaxHints = hints
+	}
+	if value := getenv("MIN_GUESS_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval < 0 {
+			return cfg, fmt.Errorf("MIN_GUESS_INTERVAL invalide : %q (exemple : 200ms)", value)
+		}
+		cfg.MinGuessInterval = interval
+	}
+	if value := getenv("IDLE_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return cfg, fmt.Errorf("IDLE_TIMEOUT invalide : %q (exemple : 60s)", value)
+		}
+		cfg.IdleTimeout = timeout
+	}
+	if value := getenv("WEBHOOK_URL"); value != "" {
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return cfg, fmt.Errorf("WEBHOOK_URL invalide : %q (URL http ou https attendue)", value)
+		}
+		cfg.WebhookURL = value
+	}
+	if value := getenv("FOLD_ACCENTS"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("FOLD_ACCENTS invalide : %q (true ou false)", value)
+		}
+		cfg.FoldAccents = enabled
+	}
+	if value := getenv("FALLBACK_WORDS"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("FALLBACK_WORDS invalide : %q (true ou false)", value)
+		}
+		cfg.FallbackWords = enabled
+	}
+	if value := getenv("TRUST_PROXY"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("TRUST_PROXY invalide : %q (true ou false)", value)
+		}
+		cfg.TrustProxy = enabled
+	}
+
+	if value := getenv("DEV"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, fmt.Errorf("DEV invalide : %q (1 ou 0)", value)
+		}
+		cfg.Dev = enabled
+	}
+	if value := getenv("RECENT_WORDS"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			return cfg, fmt.Errorf("RECENT_WORDS invalide : %q (entier positif ou nul)", value)
+		}
+		cfg.RecentWords = size
+	}
+
+	return cfg, nil
+}
+
+// Retourne le chemin d'un fichier rangé à côté du fichier des scores, comme
+// les classements, les séries ou l'archive des mots du jour
+func (cfg Config) scoresFile(name string) string {
+	return filepath.Join(filepath.Dir(cfg.ScoresPath), name)
+}
+
+// Journalise la configuration effective au démarrage
+func (cfg Config) log() {
+	slog.Info("Configuration chargée", "event", "config",
+		"port", cfg.Port,
+		"scores_path", cfg.ScoresPath,
+		"words_dir", cfg.WordsDir,
+		"session_ttl", cfg.SessionTTL.String(),
+		"max_hints", cfg.MaxHints,
+		"min_guess_interval", cfg.MinGuessInterval.String(),
+		"idle_timeout", cfg.IdleTimeout.String(),
+		"webhook", cfg.WebhookURL != "",
+		"fold_accents", cfg.FoldAccents,
+		"fallback_words", cfg.FallbackWords,
+		"recent_words", cfg.RecentWords,
+		"trust_proxy", cfg.TrustProxy,
+		"dev", cfg.Dev)
+}
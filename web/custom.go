@@ -0,0 +1,158 @@
+package main
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// customList est une liste de mots envoyée par un joueur pour une partie privée
+type customList struct {
+	Words     []string
+	CreatedAt time.Time
+}
+
+const (
+	customCategory     = "custom"  // Catégorie utilisée pour jouer avec une liste personnalisée
+	customListIDLength = 8         // Longueur de l'identifiant de liste
+	customListMaxWords = 200       // Nombre maximum de mots par liste
+	customListMaxBytes = 32 << 10  // Taille maximum du corps envoyé (32 Ko)
+	customListTTL      = time.Hour // Durée de conservation d'une liste
+)
+
+var (
+	customLists      = make(map[string]*customList) // Listes personnalisées par identifiant
+	customListsMutex sync.Mutex                     // Mutex pour sécuriser l'accès aux listes
+)
+
+// Handler pour envoyer une liste de mots personnalisée, en texte brut (un mot
+// par ligne), via le champ « words » d'un formulaire ou en fichier (« file »)
+func customListHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		err := renderTemplate(w, "custom.html", nil)
+		if err != nil {
+			http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, customListMaxBytes)
+	text, err := readCustomListBody(r)
+	if err != nil {
+		http.Error(w, "Liste invalide ou trop volumineuse.", http.StatusBadRequest)
+		return
+	}
+
+	words, skipped := parseCustomWords(text)
+	if len(words) == 0 {
+		http.Error(w, "La liste ne contient aucun mot valide.", http.StatusBadRequest)
+		return
+	}
+	if len(words) > customListMaxWords {
+		http.Error(w, "La liste contient trop de mots (200 maximum).", http.StatusBadRequest)
+		return
+	}
+
+	customListsMutex.Lock()
+	listID := generateShortCode(customListIDLength)
+	for customLists[listID] != nil {
+		listID = generateShortCode(customListIDLength)
+	}
+	customLists[listID] = &customList{Words: words, CreatedAt: time.Now()}
+	customListsMutex.Unlock()
+
+	data := struct {
+		ListID  string `json:"list_id"`
+		Count   int    `json:"words"`
+		Skipped int    `json:"skipped"`
+	}{
+		ListID:  listID,
+		Count:   len(words),
+		Skipped: skipped,
+	}
+
+	// Les clients de l'API reçoivent du JSON, les navigateurs la page de la liste
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		writeJSON(w, http.StatusCreated, data)
+		return
+	}
+	err = renderTemplate(w, "custom.html", data)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
+
+// Lit le texte de la liste depuis un fichier, un champ de formulaire ou le corps brut
+func readCustomListBody(r *http.Request) (string, error) {
+	contentType := r.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		if err := r.ParseMultipartForm(customListMaxBytes); err != nil {
+			return "", err
+		}
+		// Le champ texte et le fichier peuvent être combinés
+		text := r.FormValue("words")
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return text, nil
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		return text + "\n" + string(data), err
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		if err := r.ParseForm(); err != nil {
+			return "", err
+		}
+		return r.FormValue("words"), nil
+	default:
+		data, err := io.ReadAll(r.Body)
+		return string(data), err
+	}
+}
+
+// Découpe le texte en mots valides (un par ligne), normalisés comme les
+// listes de mots et sans doublon.
+// Retourne aussi le nombre de lignes ignorées car invalides.
+func parseCustomWords(text string) ([]string, int) {
+	var words []string
+	skipped := 0
+	for _, line := range strings.Split(text, "\n") {
+		raw := strings.TrimSpace(line)
+		if raw == "" {
+			continue
+		}
+		word, ok := validWord(raw, manager.config.FoldAccents)
+		if !ok || contains(words, word) {
+			skipped++
+			continue
+		}
+		words = append(words, word)
+	}
+	return words, skipped
+}
+
+// Tire un mot au hasard dans une liste personnalisée
+func getCustomWord(listID string) (string, error) {
+	customListsMutex.Lock()
+	defer customListsMutex.Unlock()
+
+	list, exists := customLists[strings.ToUpper(strings.TrimSpace(listID))]
+	if !exists || len(list.Words) == 0 || time.Since(list.CreatedAt) > customListTTL {
+		return "", errNoWord
+	}
+	return list.Words[rand.Intn(len(list.Words))], nil
+}
+
+// Supprime les listes personnalisées expirées
+func cleanupCustomLists() {
+	customListsMutex.Lock()
+	defer customListsMutex.Unlock()
+	for id, list := range customLists {
+		if time.Since(list.CreatedAt) > customListTTL {
+			delete(customLists, id)
+		}
+	}
+}
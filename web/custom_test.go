@@ -0,0 +1,17 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCustomWords(t *testing.T) {
+	words, skipped := parseCustomWords("Chat\n-\n  chien!\n\nchat\n42\nchou-fleur\n' '\n")
+	want := []string{"chat", "chien", "chou-fleur"}
+	if !reflect.DeepEqual(words, want) {
+		t.Errorf("words = %q, want %q", words, want)
+	}
+	if skipped != 4 {
+		t.Errorf("skipped = %d, want 4", skipped)
+	}
+}
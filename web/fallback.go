@@ -0,0 +1,45 @@
+package main
+
+import (
+	"embed"
+	"log/slog"
+)
+
+// Listes de mots minimales embarquées dans le binaire, utilisées quand aucun
+// fichier de mots n'a pu être chargé
+//
+//go:embed fallback/*.txt
+var fallbackWordsFS embed.FS
+
+// Indique si aucune liste de mots ne contient le moindre mot
+func allWordListsEmpty(words map[string]map[string][]string) bool {
+	for _, byDifficulty := range words {
+		for _, list := range byDifficulty {
+			if len(list) > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Charge les listes de mots embarquées, avec le même découpage par catégorie
+// et difficulté que les fichiers du dossier de mots
+func loadFallbackWords(fold bool) map[string]map[string][]string {
+	words := make(map[string]map[string][]string)
+
+	for _, category := range wordCategories {
+		words[category] = make(map[string][]string)
+		for _, difficulty := range wordDifficulties {
+			data, err := fallbackWordsFS.ReadFile("fallback/" + category + "_" + difficulty + ".txt")
+			if err != nil {
+				slog.Error("Liste de mots embarquée introuvable", "category", category, "difficulty", difficulty, "err", err)
+				words[category][difficulty] = []string{}
+				continue
+			}
+			words[category][difficulty] = parseWordList(data, fold)
+		}
+	}
+
+	return words
+}
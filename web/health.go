@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+)
+
+// healthStatus est le corps JSON renvoyé par /healthz
+type healthStatus struct {
+	Status     string `json:"status"`
+	Templates  bool   `json:"templates"`
+	Categories int    `json:"categories"`
+	Words      int    `json:"words"`
+}
+
+// Handler de santé pour les répartiteurs de charge : 200 quand les templates
+// et au moins une liste de mots sont chargés, 503 sinon
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	health := healthStatus{Templates: templates != nil}
+
+	manager.wordsMu.RLock()
+	for _, byDifficulty := range manager.words {
+		count := 0
+		for _, words := range byDifficulty {
+			count += len(words)
+		}
+		if count > 0 {
+			health.Categories++
+			health.Words += count
+		}
+	}
+	manager.wordsMu.RUnlock()
+
+	if !health.Templates || health.Categories == 0 {
+		health.Status = "unavailable"
+		writeJSON(w, http.StatusServiceUnavailable, health)
+		return
+	}
+
+	health.Status = "ok"
+	writeJSON(w, http.StatusOK, health)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthHandler(t *testing.T) {
+	savedManager, savedTemplates := manager, templates
+	t.Cleanup(func() { manager, templates = savedManager, savedTemplates })
+	parsed, err := parseTemplates()
+	if err != nil {
+		t.Fatalf("parseTemplates: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		words      map[string]map[string][]string
+		templates  bool
+		wantStatus int
+	}{
+		{"prêt", map[string]map[string][]string{"animals": {"easy": {"chat", "chien"}}}, true, http.StatusOK},
+		{"listes vides", map[string]map[string][]string{"animals": {"easy": {}}}, true, http.StatusServiceUnavailable},
+		{"templates absents", map[string]map[string][]string{"animals": {"easy": {"chat"}}}, false, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager = newGameManager(tt.words, nil, defaultConfig())
+			templates = nil
+			if tt.templates {
+				templates = parsed
+			}
+
+			rec := httptest.NewRecorder()
+			healthHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var health healthStatus
+			if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+				t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+			}
+			wantHealth := "ok"
+			if tt.wantStatus != http.StatusOK {
+				wantHealth = "unavailable"
+			}
+			if health.Status != wantHealth || health.Templates != tt.templates {
+				t.Errorf("body %+v, want status %q and templates %v", health, wantHealth, tt.templates)
+			}
+		})
+	}
+}
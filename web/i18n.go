@@ -0,0 +1,243 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultLang    = "fr"   // Langue par défaut des messages
+	langCookieName = "lang" // Cookie mémorisant la langue choisie
+)
+
+// Catalogues des messages affichés aux joueurs, par langue puis par clé.
+// Les messages peuvent contenir des verbes de formatage (%d, %s).
+var catalogs = map[string]map[string]string{
+	"fr": {
+		"invalid_guess":          "Veuillez entrer une lettre ou un mot valide.",
+		"word_length":            "Le mot fait %d lettres.",
+		"word_only_letter":       "Mode mot entier : proposez le mot complet, pas une lettre.",
+		"letter_already_tried":   "Vous avez déjà essayé cette lettre.",
+		"correct_guess":          "Bonne réponse !",
+		"wrong_guess":            "Mauvaise réponse.",
+		"closeness_very_close":   "Très proche !",
+		"closeness_close":        "Proche.",
+		"closeness_far":          "Loin.",
+		"word_guessed":           "Félicitations ! Vous avez deviné le mot.",
+		"all_letters_guessed":    "Félicitations ! Vous avez deviné toutes les lettres.",
+		"game_lost":              "Vous avez perdu. Le mot était : %s",
+		"game_forfeited":         "Vous avez abandonné. Le mot était : %s",
+		"game_timeout":           "Temps écoulé ! Le mot était : %s",
+		"game_idle_forfeited":    "Partie abandonnée faute de proposition. Le mot était : %s",
+		"race_lost":              "%s a trouvé le mot en premier ! Vous pouvez terminer votre partie en solo.",
+		"max_hints_reached":      "Vous avez atteint le nombre maximum d'indices.",
+		"category_clue_given":    "L'indice de catégorie a déjà été donné.",
+		"no_attempts_for_hint":   "Vous n'avez plus de tentatives pour demander un indice.",
+		"hint_letter":            "Indice : Une lettre a été révélée.",
+		"freeze_used":            "Chrono gelé : une lettre a été révélée et %d s ont été ajoutées.",
+		"freeze_none_left":       "Vous avez déjà utilisé tous vos gels du chrono.",
+		"freeze_untimed":         "Le gel du chrono n'est disponible que dans les parties chronométrées.",
+		"hint_position":          "Indice : La lettre en position %d est %s.",
+		"hint_category":          "Indice : La première lettre a été révélée. %s",
+		"undo_used":              "Vous avez déjà utilisé l'annulation pour cette partie.",
+		"undo_nothing":           "Aucune lettre à annuler.",
+		"undo_refunded":          "La lettre « %s » a été annulée et la tentative vous est rendue.",
+		"undo_done":              "La lettre « %s » a été annulée.",
+		"peek_used":              "Vous avez déjà jeté un coup d'œil au mot.",
+		"hardcore_disabled":      "Les indices, le gel du chrono, l'annulation et le coup d'œil sont désactivés en mode hardcore.",
+		"peek_info":              "Coup d'œil : il reste %d lettre(s) différente(s) à trouver : %s",
+		"hangman_stage":          "Pendu - étape %d sur %d",
+		"category_animals":       "Un animal, sauvage ou domestique.",
+		"category_technology":    "Un terme lié à l'informatique ou aux technologies.",
+		"category_countries":     "Un pays du monde.",
+		"category_random":        "Un mot choisi au hasard, sans thème particulier.",
+		"category_unknown":       "Aucune description pour cette catégorie.",
+		"required_fields":        "Tous les champs sont requis.",
+		"no_word_available":      "Aucun mot disponible pour cette catégorie ou ce niveau de difficulté.",
+		"no_daily_word":          "Aucun mot disponible pour cette catégorie.",
+		"username_required":      "Le pseudo est requis.",
+		"username_too_long":      "Le pseudo ne doit pas dépasser %d caractères.",
+		"hints_out_of_range":     "Le nombre d'indices doit être compris entre 0 et %d.",
+		"hint_cost_out_of_range": "Le coût d'un indice doit être compris entre 0 et %d tentatives.",
+		"unknown_hint_policy":    "Politique d'indice inconnue : %s.",
+		"unknown_hint_strategy":  "Stratégie d'indice inconnue : %s.",
+		"unknown_avatar":         "Avatar inconnu : %s.",
+		"scores_unavailable":     "Impossible de lire les scores.",
+		"invalid_date":           "Date invalide pour « %s » : « %s ». Format attendu : RFC3339, par exemple 2024-01-01T00:00:00Z.",
+		"invalid_date_range":     "La date de début doit précéder la date de fin.",
+		"guess_too_fast":         "Proposition trop rapide : attendez un instant avant de rejouer.",
+		"invalid_csrf":           "Token CSRF invalide.",
+		"analytics_unavailable":  "Erreur lors de la lecture des statistiques.",
+		"method_not_allowed":     "Méthode non autorisée.",
+		"invalid_json":           "Corps JSON invalide.",
+		"game_not_found":         "Partie introuvable.",
+		"score_not_found":        "Aucune partie enregistrée avec cet identifiant.",
+		"room_not_found":         "Salon introuvable.",
+		"room_full":              "Ce salon est complet ou déjà terminé.",
+		"too_many_games_created": "Trop de parties créées. Réessayez dans une minute.",
+		"forbidden":              "Accès refusé.",
+		"word_letters_only":      "Le mot doit contenir au moins une lettre, et uniquement des lettres, espaces ou tirets.",
+		"unknown_category":       "Catégorie inconnue.",
+		"unknown_difficulty":     "Niveau de difficulté inconnu.",
+		"word_exists":            "Ce mot existe déjà dans la liste.",
+		"word_save_failed":       "Impossible d'enregistrer le mot.",
+		"scores_delete_failed":   "Impossible de supprimer les scores.",
+		"import_too_large":       "Fichier trop volumineux (1 Mo maximum).",
+		"import_invalid_form":    "Formulaire multipart invalide.",
+		"import_file_missing":    "Fichier de mots manquant (champ file).",
+		"import_read_failed":     "Impossible de lire le fichier.",
+		"words_save_failed":      "Impossible d'enregistrer les mots.",
+		"words_reload_empty":     "Aucun mot trouvé dans le dossier de mots, les listes actuelles sont conservées.",
+	},
+	"en": {
+		"invalid_guess":          "Please enter a valid letter or word.",
+		"word_length":            "The word has %d letters.",
+		"word_only_letter":       "Whole-word mode: guess the full word, not a letter.",
+		"letter_already_tried":   "You have already tried this letter.",
+		"correct_guess":          "Correct!",
+		"wrong_guess":            "Wrong guess.",
+		"closeness_very_close":   "Very close!",
+		"closeness_close":        "Close.",
+		"closeness_far":          "Far off.",
+		"word_guessed":           "Congratulations! You guessed the word.",
+		"all_letters_guessed":    "Congratulations! You guessed all the letters.",
+		"game_lost":              "You lost. The word was: %s",
+		"game_forfeited":         "You gave up. The word was: %s",
+		"game_timeout":           "Time's up! The word was: %s",
+		"game_idle_forfeited":    "Game forfeited after too long without a guess. The word was: %s",
+		"race_lost":              "%s found the word first! You can finish your game solo.",
+		"max_hints_reached":      "You have reached the maximum number of hints.",
+		"category_clue_given":    "The category clue has already been given.",
+		"no_attempts_for_hint":   "You have no attempts left to ask for a hint.",
+		"hint_letter":            "Hint: a letter has been revealed.",
+		"freeze_used":            "Timer frozen: a letter has been revealed and %d s were added.",
+		"freeze_none_left":       "You have already used all your timer freezes.",
+		"freeze_untimed":         "Timer freezes are only available in timed games.",
+		"hint_position":          "Hint: the letter at position %d is %s.",
+		"hint_category":          "Hint: the first letter has been revealed. %s",
+		"undo_used":              "You have already used the undo for this game.",
+		"undo_nothing":           "No letter to undo.",
+		"undo_refunded":          "The letter « %s » was undone and the attempt given back.",
+		"undo_done":              "The letter « %s » was undone.",
+		"peek_used":              "You have already peeked at the word.",
+		"hardcore_disabled":      "Hints, timer freeze, undo and peek are disabled in hardcore mode.",
+		"peek_info":              "Peek: %d different letter(s) left to find: %s",
+		"hangman_stage":          "Hangman - stage %d of %d",
+		"category_animals":       "An animal, wild or domestic.",
+		"category_technology":    "A computing or technology term.",
+		"category_countries":     "A country of the world.",
+		"category_random":        "A random word with no particular theme.",
+		"category_unknown":       "No description for this category.",
+		"required_fields":        "All fields are required.",
+		"no_word_available":      "No word available for this category or difficulty level.",
+		"no_daily_word":          "No word available for this category.",
+		"username_required":      "A username is required.",
+		"username_too_long":      "The username must not exceed %d characters.",
+		"hints_out_of_range":     "The number of hints must be between 0 and %d.",
+		"hint_cost_out_of_range": "The cost of a hint must be between 0 and %d attempts.",
+		"unknown_hint_policy":    "Unknown hint policy: %s.",
+		"unknown_hint_strategy":  "Unknown hint strategy: %s.",
+		"unknown_avatar":         "Unknown avatar: %s.",
+		"scores_unavailable":     "Unable to read the scores.",
+		"invalid_date":           "Invalid date for %q: %q. Expected RFC3339, for example 2024-01-01T00:00:00Z.",
+		"invalid_date_range":     "The start date must be before the end date.",
+		"guess_too_fast":         "Too fast: wait a moment before guessing again.",
+		"invalid_csrf":           "Invalid CSRF token.",
+		"analytics_unavailable":  "Unable to read the statistics.",
+		"method_not_allowed":     "Method not allowed.",
+		"invalid_json":           "Invalid JSON body.",
+		"game_not_found":         "Game not found.",
+		"score_not_found":        "No recorded game with this ID.",
+		"room_not_found":         "Room not found.",
+		"room_full":              "This room is full or already finished.",
+		"too_many_games_created": "Too many games created. Try again in a minute.",
+		"forbidden":              "Access denied.",
+		"word_letters_only":      "The word must contain at least one letter, and only letters, spaces or hyphens.",
+		"unknown_category":       "Unknown category.",
+		"unknown_difficulty":     "Unknown difficulty level.",
+		"word_exists":            "This word is already in the list.",
+		"word_save_failed":       "Unable to save the word.",
+		"scores_delete_failed":   "Unable to delete the scores.",
+		"import_too_large":       "File too large (1 MB maximum).",
+		"import_invalid_form":    "Invalid multipart form.",
+		"import_file_missing":    "Missing word file (file field).",
+		"import_read_failed":     "Unable to read the file.",
+		"words_save_failed":      "Unable to save the words.",
+		"words_reload_empty":     "No words found in the words directory, the current lists are kept.",
+	},
+}
+
+// Retourne le message traduit pour la langue donnée. Une langue inconnue ou
+// une clé absente se rabat sur le français, puis sur la clé elle-même.
+func msg(lang, key string, args ...interface{}) string {
+	text, ok := catalogs[lang][key]
+	if !ok {
+		text, ok = catalogs[defaultLang][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// msgError est une erreur de validation dont le texte est traduit au moment
+// de l'affichage, selon la langue du joueur
+type msgError struct {
+	key  string
+	args []interface{}
+}
+
+func newMsgError(key string, args ...interface{}) error {
+	return &msgError{key: key, args: args}
+}
+
+func (e *msgError) Error() string {
+	return msg(defaultLang, e.key, e.args...)
+}
+
+// Retourne le code d'une erreur pour les réponses de l'API : la clé du
+// message pour une erreur traduite, "invalid_input" sinon
+func errorCode(err error) string {
+	var me *msgError
+	if errors.As(err, &me) {
+		return me.key
+	}
+	return "invalid_input"
+}
+
+// Retourne le texte d'une erreur dans la langue donnée
+func errorText(lang string, err error) string {
+	var me *msgError
+	if errors.As(err, &me) {
+		return msg(lang, me.key, me.args...)
+	}
+	return err.Error()
+}
+
+// Détermine la langue de la requête : le paramètre ?lang= (mémorisé dans un
+// cookie), puis le cookie, puis le français par défaut
+func requestLang(w http.ResponseWriter, r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		if _, ok := catalogs[lang]; ok {
+			http.SetCookie(w, &http.Cookie{
+				Name:     langCookieName,
+				Value:    lang,
+				Path:     "/",
+				MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+				SameSite: http.SameSiteLaxMode,
+			})
+			return lang
+		}
+	}
+	if cookie, err := r.Cookie(langCookieName); err == nil {
+		if _, ok := catalogs[cookie.Value]; ok {
+			return cookie.Value
+		}
+	}
+	return defaultLang
+}
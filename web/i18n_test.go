@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLangParameterSwitchesFeedback(t *testing.T) {
+	useTempScores(t)
+	tmpl, err := parseTemplates()
+	if err != nil {
+		t.Fatalf("parseTemplates: %v", err)
+	}
+	templates = tmpl
+
+	sessionID := generateSessionID()
+	game := newGame("alice", "easy", "random", "", "banane")
+	game.Practice = true
+	gameID := manager.NewGame(sessionID, game)
+
+	form := url.Values{"game": {gameID}, "csrf_token": {game.CSRFToken}, "guess": {"a"}}
+	req := httptest.NewRequest(http.MethodPost, "/game?lang=en", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+	rec := httptest.NewRecorder()
+	gameHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+
+	if view := manager.Snapshot(game); view.Lang != "en" || view.Message != msg("en", "correct_guess") {
+		t.Errorf("Lang %q, Message %q, want en and %q", view.Lang, view.Message, msg("en", "correct_guess"))
+	}
+	if msg("en", "correct_guess") == msg("fr", "correct_guess") {
+		t.Error("correct_guess has the same text in English and French")
+	}
+
+	var langCookie *http.Cookie
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == langCookieName {
+			langCookie = cookie
+		}
+	}
+	if langCookie == nil || langCookie.Value != "en" {
+		t.Fatalf("lang cookie = %v, want en", langCookie)
+	}
+
+	// Le cookie suffit ensuite, et une langue inconnue est ignorée
+	req = httptest.NewRequest(http.MethodGet, "/game?lang=xx", nil)
+	req.AddCookie(langCookie)
+	if lang := requestLang(httptest.NewRecorder(), req); lang != "en" {
+		t.Errorf("requestLang with cookie = %q, want en", lang)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/game", nil)
+	if lang := requestLang(httptest.NewRecorder(), req); lang != defaultLang {
+		t.Errorf("requestLang without cookie = %q, want %q", lang, defaultLang)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Crée le logger de l'application. LOG_FORMAT=json produit une ligne JSON par
+// événement pour les outils de supervision ; sinon un format texte lisible.
+// LOG_LEVEL=debug affiche aussi les messages de chargement.
+func newLogger(format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	if strings.EqualFold(os.Getenv("LOG_LEVEL"), "debug") {
+		opts.Level = slog.LevelDebug
+	}
+
+	if strings.EqualFold(format, "json") {
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, opts))
+}
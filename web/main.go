@@ -1,133 +1,669 @@
 package main
 
 import (
+	"context"
 	crand "crypto/rand" // Alias pour crypto/rand
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"hash/fnv"
 	"html/template"
-	"log"
+	"io"
+	"log/slog"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // Game représente l'état d'une partie en cours ou terminée
 type Game struct {
-	Username       string
-	Difficulty     string
-	Category       string
-	Word           string
-	GuessedLetters []string
-	AttemptsLeft   int
-	Status         string // "ongoing", "won", "lost"
-	Message        string // Message de feedback
-	MessageType    string // "success" ou "error"
-	CreatedAt      time.Time
-	HintsUsed      int    // Nombre d'indices utilisés
-	CSRFToken      string // Token CSRF
-	Theme          string // Thème choisi
+	ID                string // Identifiant de la partie dans la session
+	Username          string
+	Difficulty        string
+	Category          string
+	SourceCategory    string // Catégorie d'origine du mot, différente de Category en catégorie "random"
+	Word              string
+	WordTheme         string // Thème de la sous-liste d'où vient le mot, vide pour la liste principale
+	WordRating        int    // Difficulté calculée du mot (voir scoreWordDifficulty)
+	WordIndex         int    // Position du mot dans sa liste d'origine, à partir de 1 (0 si inconnue)
+	GuessedLetters    []string
+	AttemptsLeft      int
+	MaxAttempts       int    // Nombre de tentatives au départ
+	Status            string // "ongoing", "won", "lost"
+	Message           string // Message de feedback
+	MessageType       string // "success" ou "error"
+	CreatedAt         time.Time
+	LastActivity      time.Time
+	LastGuess         time.Time // Dernière proposition, seule à repousser l'abandon d'office (voir checkIdle)
+	Deadline          time.Time
+	Guesses           []GuessEvent
+	HintsUsed         int    // Nombre d'indices utilisés
+	MaxHints          int    // Nombre maximum d'indices pour cette partie
+	HintPolicy        string // "costly", "free" ou "category-clue"
+	HintCost          int    // Tentatives déduites par indice, hors politique "free" (voir HintAttempts)
+	HintStrategy      string // Lettre révélée par un indice : "first", "vowel" ou "random"
+	HintClue          string // Description de la catégorie donnée par l'indice
+	FreezesUsed       int    // Nombre de gels du chrono utilisés
+	MaxFreezes        int    // Nombre maximum de gels du chrono pour cette partie
+	FrozenSeconds     int    // Secondes ajoutées au chrono par les gels
+	WrongGuesses      int    // Nombre de mauvaises propositions
+	DurationSeconds   int    // Durée de la partie, calculée à la fin
+	CSRFToken         string // Token CSRF
+	ResumeCode        string // Code pour reprendre la partie depuis un autre appareil
+	WatchCode         string // Code pour suivre la partie en spectateur, sans pouvoir jouer
+	Avatar            string // Nom de l'avatar choisi par le joueur (voir avatars)
+	Theme             string // Thème choisi
+	Lang              string // Langue des messages ("fr" ou "en")
+	Mode              string // "normal", "daily", "race" ou "hardcore"
+	WordOnlyMode      bool   // Seules les propositions de mots entiers sont acceptées
+	CasualMode        bool   // Un mot faux indique à quel point il était proche
+	AccentInsensitive bool   // Une lettre sans accent révèle aussi ses variantes accentuées, et inversement
+	Practice          bool   // Entraînement : tentatives illimitées, pas de score enregistré
+	ShowThemeClue     bool   // La description de la catégorie est affichée dès le premier coup
+	BiasedWord        bool   // Le mot a été tiré en favorisant la difficulté du niveau
+	RoomCode          string // Code du salon pour le mode course
+	Forfeited         bool   // La partie a été abandonnée par le joueur
+	UndoUsed          bool   // L'annulation de la dernière lettre a été utilisée
+	PeekUsed          bool   // Le coup d'œil gratuit a été utilisé
+	TimedOut          bool   // La partie a été perdue faute de temps
+	CurrentStreak     int    // Série de victoires du joueur, connue à la fin de la partie
+	AdaptiveLevel     string // Niveau adaptatif du joueur pour une partie en "auto", mis à jour à la fin (vide sinon)
+	SortGuesses       bool   `json:"-"` // Affichage seulement : lettres essayées triées de a à z (préférence du joueur)
 }
 
+// Expired indique si la partie est restée inactive plus longtemps que ttl
+// (Config.SessionTTL). Les parties sauvegardées avant l'ajout de LastActivity
+// se basent sur leur date de création.
+func (g *Game) Expired(ttl time.Duration) bool {
+	last := g.LastActivity
+	if last.IsZero() {
+		last = g.CreatedAt
+	}
+	return time.Since(last) > ttl
+}
+
+// CorrectLetters retourne les lettres du mot qui ont été devinées, sans
+// doublon et dans l'ordre du mot
+func (g *Game) CorrectLetters() []string {
+	return g.wordLetters(true)
+}
+
+// MissedLetters retourne les lettres du mot qui n'ont jamais été devinées,
+// sans doublon et dans l'ordre du mot
+func (g *Game) MissedLetters() []string {
+	return g.wordLetters(false)
+}
+
+// HintAttempts retourne le nombre de tentatives que coûtera le prochain
+// indice : zéro avec la politique "free", HintCost sinon
+func (g *Game) HintAttempts() int {
+	if g.HintPolicy == "free" {
+		return 0
+	}
+	return g.HintCost
+}
+
+// FreezesRemaining retourne le nombre de gels du chrono encore disponibles,
+// jamais négatif
+func (g *Game) FreezesRemaining() int {
+	return max(g.MaxFreezes-g.FreezesUsed, 0)
+}
+
+// HintsRemaining retourne le nombre d'indices encore disponibles dans la
+// partie, jamais négatif
+func (g *Game) HintsRemaining() int {
+	remaining := g.MaxHints - g.HintsUsed
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// MaskedWord retourne le mot tel qu'affiché au joueur. En mode mot entier,
+// seule la longueur est visible tant que le mot n'est pas trouvé.
+func (g *Game) MaskedWord() string {
+	if g.WordOnlyMode && g.Status == "ongoing" {
+		return displayWord(g.Word, nil, false)
+	}
+	return displayWord(g.Word, g.GuessedLetters, g.AccentInsensitive)
+}
+
+// Retourne une copie de la partie qui peut être lue sans verrou, par exemple
+// pendant le rendu d'un template. Les slices sont dupliquées pour que les
+// propositions suivantes ne modifient pas la copie.
+// Doit être appelée avec le verrou des parties.
+func (g *Game) snapshot() Game {
+	view := *g
+	view.GuessedLetters = append([]string(nil), g.GuessedLetters...)
+	view.Guesses = append([]GuessEvent(nil), g.Guesses...)
+	return view
+}
+
+// ThemeClue retourne la description de la catégorie affichée pour orienter le
+// joueur, ou une chaîne vide si l'option n'est pas activée
+func (g *Game) ThemeClue() string {
+	if !g.ShowThemeClue {
+		return ""
+	}
+	return categoryDescription(g.Lang, g.Category)
+}
+
+// Vérifie si le mot contient la lettre, en ignorant les accents si la partie
+// est en mode insensible aux accents
+func (g *Game) hasLetter(letter string) bool {
+	for _, c := range g.Word {
+		if sameLetter(string(c), letter, g.AccentInsensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// Vérifie si la lettre, ou une de ses variantes en mode insensible aux
+// accents, a déjà été proposée
+func (g *Game) letterGuessed(letter string) bool {
+	return containsLetter(g.GuessedLetters, letter, g.AccentInsensitive)
+}
+
+// Retourne les lettres distinctes du mot selon qu'elles ont été devinées ou non
+func (g *Game) wordLetters(guessed bool) []string {
+	letters := []string{}
+	for _, c := range g.Word {
+		letter := string(c)
+		if isSeparator(c) || containsLetter(letters, letter, g.AccentInsensitive) {
+			continue
+		}
+		if g.letterGuessed(letter) == guessed {
+			letters = append(letters, letter)
+		}
+	}
+	return letters
+}
+
+// Retourne l'état de chaque lettre de l'alphabet du mot (de a à z pour
+// l'écriture latine) pour le clavier à l'écran : "unused" si elle n'a pas été
+// essayée, "correct" si elle est dans le mot, "wrong" sinon
+func letterStates(game *Game) map[string]string {
+	states := make(map[string]string)
+	for _, c := range scriptAlphabet(game.Script()) {
+		letter := string(c)
+		switch {
+		case !game.letterGuessed(letter):
+			states[letter] = "unused"
+		case game.hasLetter(letter):
+			states[letter] = "correct"
+		default:
+			states[letter] = "wrong"
+		}
+	}
+	return states
+}
+
+// Retourne une copie des lettres proposées triées de a à z, les lettres
+// accentuées à côté de leur forme sans accent. L'ordre enregistré dans la
+// partie n'est pas modifié.
+func sortedGuesses(game *Game) []string {
+	sorted := append([]string(nil), game.GuessedLetters...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := accentFolder.Replace(sorted[i]), accentFolder.Replace(sorted[j])
+		if a != b {
+			return a < b
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+// Détermine l'ordre d'affichage des lettres essayées : le paramètre ?order=
+// ("alpha" ou "played", mémorisé dans un cookie), puis le cookie. Retourne
+// true pour l'ordre alphabétique ; l'ordre de jeu est celui par défaut.
+func sortGuessesPreference(w http.ResponseWriter, r *http.Request) bool {
+	order := r.URL.Query().Get("order")
+	if order == "alpha" || order == "played" {
+		http.SetCookie(w, &http.Cookie{
+			Name:     guessOrderCookieName,
+			Value:    order,
+			Path:     "/",
+			MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+			SameSite: http.SameSiteLaxMode,
+		})
+		return order == "alpha"
+	}
+	if cookie, err := r.Cookie(guessOrderCookieName); err == nil {
+		return cookie.Value == "alpha"
+	}
+	return false
+}
+
+// Retourne les lettres proposées qui ne sont pas dans le mot, dans l'ordre où
+// elles ont été essayées. Les lettres révélées par un indice sont toujours
+// dans le mot et n'y figurent donc jamais.
+func wrongGuesses(game *Game) []string {
+	wrong := []string{}
+	for _, letter := range game.GuessedLetters {
+		if !game.hasLetter(letter) {
+			wrong = append(wrong, letter)
+		}
+	}
+	return wrong
+}
+
+// Version actuelle du format des lignes du fichier des scores. Les lignes
+// écrites avant l'ajout du champ "v" sont de version 1.
+const scoreVersion = 3
+
 // Score représente une entrée dans le leaderboard
 type Score struct {
-	Username    string `json:"username"`
-	Difficulty  string `json:"difficulty"`
-	Category    string `json:"category"`
-	Status      string `json:"status"`
-	Word        string `json:"word"`
-	HintsUsed   int    `json:"hints_used"`
-	Timestamp   int64  `json:"timestamp"`
+	Version           int          `json:"v"`
+	ID                string       `json:"id,omitempty"`
+	Username          string       `json:"username"`
+	Avatar            string       `json:"avatar,omitempty"`
+	Difficulty        string       `json:"difficulty"`
+	Category          string       `json:"category"`
+	SourceCategory    string       `json:"source_category,omitempty"`
+	Status            string       `json:"status"`
+	Word              string       `json:"word"`
+	WordRating        int          `json:"word_rating,omitempty"`
+	WordIndex         int          `json:"word_index,omitempty"`
+	HintsUsed         int          `json:"hints_used"`
+	WrongGuesses      int          `json:"wrong_guesses"`
+	Points            int          `json:"points"`
+	BasePoints        int          `json:"base_points,omitempty"`
+	BlitzBonus        int          `json:"blitz_bonus,omitempty"`
+	DurationSeconds   int          `json:"duration_seconds,omitempty"`
+	Mode              string       `json:"mode,omitempty"`
+	WordOnly          bool         `json:"word_only,omitempty"`
+	AccentInsensitive bool         `json:"accent_insensitive,omitempty"`
+	Forfeited         bool         `json:"forfeited,omitempty"`
+	Guesses           []GuessEvent `json:"guesses,omitempty"`
+	Timestamp         int64        `json:"timestamp"`
 }
 
 // Variables globales
 var (
-	templates = template.Must(template.New("").Funcs(template.FuncMap{
-		"displayWord": displayWord,
-		"title":       strings.Title, // Fonction pour capitaliser la première lettre
-		"timeFormat":  func(timestamp int64) string {
-			t := time.Unix(timestamp, 0)
-			return t.Format("02/01/2006 15:04:05")
-		},
-	}).ParseGlob("templates/*.html"))
-
-	games           = make(map[string]*Game) // Map pour stocker les parties en cours
-	gamesMutex      sync.Mutex                // Mutex pour sécuriser l'accès concurrent
-	wordsByCategory = loadWords()             // Mots chargés depuis les fichiers
-	scoreFilePath   = "scores/scores.json"    // Chemin vers le fichier des scores
-	sessionExpiration = 30 * time.Minute      // Expiration des sessions
-	maxHints        = 2                       // Nombre maximum d'indices
+	wordsFetchTimeout = 10 * time.Second      // Délai maximum pour télécharger une liste de mots
+	wordCategories  = []string{"animals", "technology", "countries", "random"} // Catégories des listes de mots
+	wordDifficulties = []string{"easy", "medium", "hard"}                      // Difficultés des listes de mots
+	randomCategory  = "random"                                                  // Catégorie qui mélange toutes les autres
+	scoresMutex     sync.Mutex                // Mutex pour sérialiser l'accès au fichier des scores
+	scoresCache     []Score                   // Scores déjà lus depuis le fichier, dans l'ordre d'écriture
+	scoresCacheValid bool                     // Faux tant que le fichier doit être relu
+	sortedScoresCache = map[string][]Score{}  // Scores triés, par critère de tri
+	gamesStatePath  = "games/state.json"      // Chemin vers la sauvegarde des parties en cours
+	persistInterval = 30 * time.Second        // Intervalle de sauvegarde des parties
+	shutdownTimeout = 10 * time.Second        // Délai maximum pour terminer les requêtes à l'arrêt
+	maxHintsLimit   = 5                       // Nombre d'indices maximum qu'un joueur peut choisir
+	maxHintCost     = 2                       // Nombre de tentatives maximum que peut coûter un indice
+	guessOrderCookieName = "guess_order"      // Cookie mémorisant l'ordre d'affichage des lettres essayées
+	resumeCodeLength = 8                      // Longueur du code de reprise
+	gameIDLength    = 6                       // Longueur de l'ID d'une partie dans une session
+	scoreIDLength   = 10                      // Longueur de l'identifiant d'une partie enregistrée
+	maxUsernameLength = 20                    // Longueur maximum d'un pseudo
+
+	// Points de base attribués pour une victoire selon la difficulté
+	pointsByDifficulty = map[string]int{
+		"easy":   10,
+		"medium": 20,
+		"hard":   30,
+	}
+	wrongGuessPenalty = 2 // Points retirés par mauvaise proposition
+	hintPenalty       = 3 // Points retirés par indice utilisé
+	blitzBonusMax     = 20 // Bonus blitz d'une victoire avec tout le temps restant
+
+	// Pagination du leaderboard
+	defaultPageSize = 25
+	maxPageSize     = 100
+
+	// Mode hardcore : peu de tentatives, aucune aide et des points multipliés
+	hardcoreAttempts         = 3
+	hardcorePointsMultiplier = 2
+
+	// Nombre de tentatives au départ selon la difficulté
+	attemptsByDifficulty = map[string]int{
+		"easy":   8,
+		"medium": 6,
+		"hard":   4,
+	}
+	defaultAttempts = 6 // Tentatives pour une difficulté inconnue
+
+	// Temps imparti pour trouver le mot selon la difficulté
+	timeLimitByDifficulty = map[string]time.Duration{
+		"easy":   180 * time.Second,
+		"medium": 180 * time.Second,
+		"hard":   90 * time.Second,
+	}
+	defaultTimeLimit = 180 * time.Second // Temps imparti pour une difficulté inconnue
+
+	// Politiques d'indice : "costly" coûte une tentative, "free" ne coûte rien
+	// et "category-clue" révèle la première lettre et une description de la catégorie
+	hintPolicies      = []string{"costly", "free", "category-clue"}
+	defaultHintPolicy = "costly"
+
+	// Stratégies d'indice : "first" révèle la première lettre manquante du mot,
+	// "vowel" une voyelle en priorité et "random" une lettre au hasard
+	hintStrategies      = []string{"first", "vowel", "random"}
+	defaultHintStrategy = "first"
+	vowels              = "aeiouyàâäéèêëîïôöùûüÿ"
+
+	// Avatars proposés au joueur, par nom. Seuls ces noms sont acceptés : le
+	// leaderboard n'affiche jamais une valeur saisie librement.
+	avatars = map[string]string{
+		"neutral": "🙂",
+		"cat":     "🐱",
+		"fox":     "🦊",
+		"owl":     "🦉",
+		"robot":   "🤖",
+		"alien":   "👽",
+		"ghost":   "👻",
+		"dragon":  "🐉",
+	}
+	defaultAvatar = "neutral"
+
+	// Tentatives déduites par indice selon la difficulté, quand le joueur ne
+	// choisit pas lui-même
+	hintCostByDifficulty = map[string]int{
+		"easy":   1,
+		"medium": 1,
+		"hard":   2,
+	}
+	defaultHintCost = 1 // Coût d'un indice pour une difficulté inconnue
+
+	// Gel du chrono : chaque gel révèle une lettre et repousse l'heure limite
+	maxFreezes  = 1                // Nombre de gels par partie chronométrée
+	freezeBonus = 10 * time.Second // Temps ajouté au chrono par un gel
+
+	// Plafond d'indices selon la difficulté
+	hintsCapByDifficulty = map[string]int{
+		"easy":   5,
+		"medium": 4,
+		"hard":   3,
+	}
+
+	dailyDifficulty = "medium" // Liste de mots utilisée pour le défi du jour
+
+	// Modes où Config.MinGuessInterval impose un délai entre deux propositions,
+	// pour décourager les clients automatisés
+	competitiveModes = []string{"daily", "race"}
+
+	idleSweepInterval = 10 * time.Second // Intervalle de recherche des parties inactives (voir Config.IdleTimeout)
 )
 
 func main() {
+	slog.SetDefault(newLogger(os.Getenv("LOG_FORMAT")))
+
 	// Initialiser la graine aléatoire pour math/rand
 	rand.Seed(time.Now().UnixNano())
 
+	// Charger la configuration depuis l'environnement
+	cfg, err := loadConfig(os.Getenv)
+	if err != nil {
+		slog.Error("Configuration invalide", "err", err)
+		os.Exit(1)
+	}
+	cfg.log()
+	manager = newGameManager(loadWords(cfg.WordsDir, cfg.FoldAccents), loadThemedWords(cfg.WordsDir, cfg.FoldAccents), cfg)
+
+	// Un template invalide est signalé avec son fichier plutôt que par une panique
+	templates, err = parseTemplates()
+	if err != nil {
+		slog.Error("Impossible de charger les templates", "err", err)
+		os.Exit(1)
+	}
+
+	// Remplacer les listes locales par celles du serveur de mots, si configuré
+	if base := os.Getenv("WORDS_URL"); base != "" {
+		remoteWords := loadWordsFromURL(base, cfg.WordsDir, cfg.FoldAccents)
+		manager.wordsMu.Lock()
+		manager.words = remoteWords
+		manager.wordsMu.Unlock()
+	}
+
+	// Sans aucun mot, chaque partie échouerait : se rabattre sur les listes
+	// embarquées ou refuser de démarrer
+	manager.wordsMu.Lock()
+	if allWordListsEmpty(manager.words) {
+		if !cfg.FallbackWords {
+			manager.wordsMu.Unlock()
+			slog.Error("Aucune liste de mots disponible : vérifiez WORDS_DIR ou WORDS_URL", "words_dir", cfg.WordsDir)
+			os.Exit(1)
+		}
+		slog.Warn("Aucune liste de mots disponible, utilisation des listes embarquées", "words_dir", cfg.WordsDir)
+		manager.words = loadFallbackWords(cfg.FoldAccents)
+	}
+	manager.wordsMu.Unlock()
+
+	// Restaurer les parties sauvegardées avant l'arrêt précédent
+	loadGames()
+	loadAnalytics()
+
 	// Lancer la goroutine de nettoyage des sessions
 	go cleanupSessions()
 
+	// Lancer la goroutine de sauvegarde des parties
+	go persistGamesPeriodically()
+
+	// Lancer la goroutine d'abandon des parties chronométrées inactives
+	go sweepIdleGames()
+
 	// Configurer les routes
-	http.HandleFunc("/", indexHandler)
+	http.HandleFunc("/", rateLimitGameCreation(indexHandler))
 	http.HandleFunc("/game", gameHandler)
 	http.HandleFunc("/end", endHandler)
 	http.HandleFunc("/scores", scoresHandler)
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
+	http.HandleFunc("/scores.csv", scoresCSVHandler)
+	http.HandleFunc("/daily", rateLimitGameCreation(dailyHandler))
+	http.HandleFunc("/archive", archiveHandler)
+	http.HandleFunc("/stats", statsHandler)
+	http.HandleFunc("/ranking", rankingHandler)
+	http.HandleFunc("/replay", replayHandler)
+	http.HandleFunc("/resume", rateLimitGameCreation(resumeHandler))
+	http.HandleFunc("/watch/", watchHandler)
+	http.HandleFunc("/custom", customListHandler)
+	http.HandleFunc("/room", rateLimitGameCreation(createRoomHandler))
+	http.HandleFunc("/room/", rateLimitGameCreation(roomHandler))
+	http.HandleFunc("/healthz", healthHandler)
+	http.HandleFunc("/admin/words", addWordHandler)
+	http.HandleFunc("/admin/words/stats", wordStatsHandler)
+	http.HandleFunc("/admin/words/import", importWordsHandler)
+	http.HandleFunc("/admin/reload", reloadWordsHandler)
+	http.HandleFunc("/api/game", rateLimitGameCreation(apiStartHandler))
+	http.HandleFunc("/api/game/guess", apiGuessHandler)
+	http.HandleFunc("/api/game/state", apiStateHandler)
+	http.HandleFunc("/api/meta", apiMetaHandler)
+	http.HandleFunc("/api/csrf", apiCSRFHandler)
+	http.HandleFunc("/api/username/check", apiUsernameCheckHandler)
+	http.HandleFunc("/api/scores", apiScoresHandler)
+	http.HandleFunc("/api/scores/", apiScoreHandler)
+	http.HandleFunc("/api/analytics/letters", letterAnalyticsHandler)
+	http.HandleFunc("/api/analytics/attempts", attemptsAnalyticsHandler)
+	http.Handle("/static/", staticHandler())
+
+	server := &http.Server{Addr: ":" + cfg.Port}
+	go func() {
+		slog.Info("Serveur démarré", "event", "server_start", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("Erreur du serveur HTTP", "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	// Attendre un signal d'arrêt (Ctrl+C ou SIGTERM lors d'un déploiement)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	shutdownServer(server)
+}
+
+// Arrête le serveur proprement : termine les requêtes en cours, attend les
+// écritures de scores puis sauvegarde les parties en cours sur disque
+func shutdownServer(server *http.Server) {
+	slog.Info("Arrêt du serveur en cours", "event", "server_shutdown")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Error("Erreur lors de l'arrêt du serveur", "err", err)
+	}
+
+	// Attendre la fin d'une éventuelle écriture de score
+	scoresMutex.Lock()
+	scoresMutex.Unlock()
 
-	log.Println("Serveur démarré sur http://localhost:8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	persistGames()
+	persistAnalytics()
+	slog.Info("Serveur arrêté", "event", "server_stopped")
 }
 
 // Fonction pour charger les mots depuis les fichiers
-func loadWords() map[string]map[string][]string {
-	categories := []string{"animals", "technology", "countries", "random"}
-	difficulties := []string{"easy", "medium", "hard"}
+func loadWords(dir string, fold bool) map[string]map[string][]string {
+	words := make(map[string]map[string][]string)
+
+	for _, category := range wordCategories {
+		words[category] = make(map[string][]string)
+		for _, difficulty := range wordDifficulties {
+			words[category][difficulty] = readWordFile(dir, category, difficulty, fold)
+		}
+	}
+
+	return words
+}
+
+// Charge les listes de mots depuis un serveur HTTP (un fichier texte par
+// catégorie et difficulté, ex. <base>/animals_easy.txt). En cas d'erreur,
+// la copie locale de la liste concernée est utilisée.
+func loadWordsFromURL(base, dir string, fold bool) map[string]map[string][]string {
+	client := &http.Client{Timeout: wordsFetchTimeout}
+	base = strings.TrimSuffix(base, "/")
 
 	words := make(map[string]map[string][]string)
 
-	for _, category := range categories {
+	for _, category := range wordCategories {
 		words[category] = make(map[string][]string)
-		for _, difficulty := range difficulties {
-			filePath := filepath.Join("words", category+"_"+difficulty+".txt")
-			log.Printf("Chargement des mots depuis : %s", filePath)
-			data, err := os.ReadFile(filePath)
+		for _, difficulty := range wordDifficulties {
+			listURL := base + "/" + category + "_" + difficulty + ".txt"
+			slog.Debug("Téléchargement des mots", "url", listURL)
+			data, err := fetchWordList(client, listURL)
 			if err != nil {
-				log.Printf("Erreur de lecture du fichier %s: %v\n", filePath, err)
-				words[category][difficulty] = []string{}
+				slog.Error("Erreur de téléchargement des mots, utilisation de la copie locale", "url", listURL, "err", err)
+				words[category][difficulty] = readWordFile(dir, category, difficulty, fold)
 				continue
 			}
-			lines := strings.Split(string(data), "\n")
-			var categoryWords []string
-			for _, line := range lines {
-				word := strings.TrimSpace(line)
-				if word != "" {
-					categoryWords = append(categoryWords, word)
-				}
-			}
-			words[category][difficulty] = categoryWords
+			words[category][difficulty] = parseWordList(data, fold)
 		}
 	}
 
 	return words
 }
 
+// Télécharge une liste de mots et refuse toute réponse autre que 200
+func fetchWordList(client *http.Client, listURL string) ([]byte, error) {
+	resp, err := client.Get(listURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("statut HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Lit la liste de mots locale d'une catégorie et d'une difficulté
+func readWordFile(dir, category, difficulty string, fold bool) []string {
+	filePath := wordFilePath(dir, category, difficulty)
+	slog.Debug("Chargement des mots", "path", filePath)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		slog.Error("Erreur de lecture du fichier de mots", "path", filePath, "err", err)
+		return []string{}
+	}
+	return parseWordList(data, fold)
+}
+
+// Découpe une liste de mots, un mot par ligne, en ignorant les lignes vides.
+// Chaque mot est normalisé ; les lignes qui ne donnent pas un mot valide sont
+// signalées dans les logs puis ignorées.
+func parseWordList(data []byte, fold bool) []string {
+	lines := strings.Split(string(data), "\n")
+	var categoryWords []string
+	for i, line := range lines {
+		raw := strings.TrimSpace(line)
+		if raw == "" {
+			continue
+		}
+		word, ok := validWord(raw, fold)
+		if !ok {
+			slog.Warn("Ligne de la liste de mots ignorée", "line", i+1, "value", raw)
+			continue
+		}
+		categoryWords = append(categoryWords, word)
+	}
+	return categoryWords
+}
+
+// Normalise un mot saisi ou lu dans une liste et indique s'il peut être joué :
+// uniquement des lettres, espaces et tirets, et au moins une lettre
+func validWord(raw string, fold bool) (string, bool) {
+	word := normalizeWord(raw, fold)
+	if word == "" || !isAlpha(word) || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+		return "", false
+	}
+	return word, true
+}
+
+// Remplace les lettres accentuées par leur forme sans accent
+var accentFolder = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a", "á", "a", "ã", "a", "å", "a",
+	"ç", "c",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "í", "i", "ì", "i",
+	"ñ", "n",
+	"ô", "o", "ö", "o", "ó", "o", "ò", "o", "õ", "o",
+	"ù", "u", "û", "u", "ü", "u", "ú", "u",
+	"ÿ", "y", "ý", "y",
+	"æ", "ae", "œ", "oe",
+	"ά", "α", "έ", "ε", "ή", "η", "ί", "ι", "ϊ", "ι", "ΐ", "ι",
+	"ό", "ο", "ύ", "υ", "ϋ", "υ", "ΰ", "υ", "ώ", "ω",
+)
+
+// Met un mot des listes sous sa forme canonique : en minuscules, sans
+// ponctuation autour ni espaces superflus, et sans accents si fold est activé
+// (Config.FoldAccents)
+func normalizeWord(word string, fold bool) string {
+	word = strings.ToLower(word)
+	word = strings.TrimFunc(word, func(c rune) bool {
+		return unicode.IsPunct(c) || unicode.IsSymbol(c) || unicode.IsSpace(c)
+	})
+	word = normalizeSpaces(word)
+	if fold {
+		word = accentFolder.Replace(word)
+	}
+	return word
+}
+
+// Retourne le chemin du fichier de mots d'une catégorie et d'une difficulté
+func wordFilePath(dir, category, difficulty string) string {
+	return filepath.Join(dir, category+"_"+difficulty+".txt")
+}
+
 // Handler pour la page d'accueil
 func indexHandler(w http.ResponseWriter, r *http.Request) {
-	// Si une partie est en cours, rediriger vers la page de jeu
+	lang := requestLang(w, r)
 	sessionID := getSessionID(r)
-	if sessionID != "" {
-		gamesMutex.Lock()
-		game, exists := games[sessionID]
-		gamesMutex.Unlock()
-		if exists && game.Status == "ongoing" {
-			http.Redirect(w, r, "/game", http.StatusSeeOther)
-			return
-		}
-	}
 
 	// Gérer le formulaire de démarrage de partie
 	if r.Method == http.MethodPost {
@@ -137,49 +673,108 @@ func indexHandler(w http.ResponseWriter, r *http.Request) {
 		theme := r.FormValue("theme")
 
 		if username == "" || difficulty == "" || category == "" || theme == "" {
-			http.Error(w, "Tous les champs sont requis.", http.StatusBadRequest)
+			http.Error(w, msg(lang, "required_fields"), http.StatusBadRequest)
 			return
 		}
 
-		word := getRandomWord(difficulty, category)
-		if word == "erreur" {
-			http.Error(w, "Aucun mot disponible pour cette catégorie ou ce niveau de difficulté.", http.StatusInternalServerError)
+		username, err := sanitizeUsername(username)
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusBadRequest)
 			return
 		}
+		difficulty, adaptive := resolveDifficulty(username, difficulty)
 
-		game := &Game{
-			Username:       username,
-			Difficulty:     difficulty,
-			Category:       category,
-			Word:           strings.ToLower(word),
-			GuessedLetters: []string{},
-			AttemptsLeft:   6,
-			Status:         "ongoing",
-			CreatedAt:      time.Now(),
-			HintsUsed:      0,
-			Theme:          theme,
-			CSRFToken:      generateCSRFToken(),
+		hints, err := parseMaxHints(r.FormValue("hints"), difficulty)
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusBadRequest)
+			return
 		}
 
-		sessionID = generateSessionID()
-		gamesMutex.Lock()
-		games[sessionID] = game
-		gamesMutex.Unlock()
+		hintCost, err := parseHintCost(r.FormValue("hint_cost"), difficulty)
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusBadRequest)
+			return
+		}
 
-		http.SetCookie(w, &http.Cookie{
-			Name:     "session_id",
-			Value:    sessionID,
-			Path:     "/",
-			HttpOnly: true,
-			// Secure:   true, // Décommentez si vous utilisez HTTPS
-		})
+		hintPolicy, err := parseHintPolicy(r.FormValue("hint_policy"))
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusBadRequest)
+			return
+		}
+
+		hintStrategy, err := parseHintStrategy(r.FormValue("hint_strategy"))
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusBadRequest)
+			return
+		}
+
+		avatar, err := parseAvatar(r.FormValue("avatar"))
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusBadRequest)
+			return
+		}
+
+		biased := r.FormValue("biased") == "on"
+		word, source, wordTheme, err := getFreshWord(username, difficulty, category, r.FormValue("listId"), biased, manager.config.RecentWords)
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusInternalServerError)
+			return
+		}
+
+		game := newGame(username, difficulty, category, theme, word)
+		game.MaxHints = hints
+		game.HintCost = hintCost
+		game.HintPolicy = hintPolicy
+		game.HintStrategy = hintStrategy
+		game.Avatar = avatar
+		game.BiasedWord = biased
+		game.SourceCategory = source
+		game.WordTheme = wordTheme
+		game.WordIndex = manager.wordIndex(source, difficulty, game.WordTheme, word)
+		game.Lang = lang
+		if adaptive {
+			game.AdaptiveLevel = difficulty
+		}
+		if r.FormValue("hardcore") == "on" {
+			makeHardcore(game)
+		}
+		if r.FormValue("word_only") == "on" {
+			makeWordOnly(game)
+		}
+		game.CasualMode = r.FormValue("casual") == "on"
+		if r.FormValue("accent_insensitive") == "on" || game.CasualMode {
+			enableAccentInsensitive(game)
+		}
+		if r.FormValue("practice") == "on" {
+			makePractice(game)
+		}
+		if r.FormValue("theme_clue") == "on" {
+			enableThemeClue(game)
+		}
+		if sessionID == "" {
+			sessionID = generateSessionID()
+		}
+
+		// Par défaut les parties en cours sont conservées à côté de la nouvelle ;
+		// avec ?force=1 le joueur choisit explicitement de les abandonner
+		if r.URL.Query().Get("force") == "1" {
+			manager.ForfeitSession(sessionID)
+		}
+		manager.NewGame(sessionID, game)
 
-		http.Redirect(w, r, "/game", http.StatusSeeOther)
+		setSessionCookie(w, r, sessionID)
+
+		http.Redirect(w, r, gameURL("/game", game), http.StatusSeeOther)
 		return
 	}
 
-	// Afficher la page d'accueil
-	err := templates.ExecuteTemplate(w, "index.html", nil)
+	// Afficher la page d'accueil avec les parties en cours de la session
+	data := struct {
+		ActiveGames []Game
+	}{
+		ActiveGames: manager.Active(sessionID),
+	}
+	err := renderTemplate(w, "index.html", data)
 	if err != nil {
 		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
 	}
@@ -187,192 +782,1177 @@ func indexHandler(w http.ResponseWriter, r *http.Request) {
 
 // Handler pour la page de jeu
 func gameHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
 	sessionID := getSessionID(r)
 	if sessionID == "" {
+		clearSessionCookie(w, r)
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
 	}
 
-	gamesMutex.Lock()
-	game, exists := games[sessionID]
-	gamesMutex.Unlock()
-
+	game, exists := manager.Get(sessionID, r.FormValue("game"))
 	if !exists {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
 	}
 
+	// La partie n'est modifiée que sous verrou, et la page est rendue à
+	// partir d'une copie pour ne pas la lire pendant qu'une autre requête
+	// la modifie
+	validToken := true
+	view := manager.Update(game, func(game *Game) {
+		// Une requête arrivée après l'heure limite fait perdre la partie
+		// au lieu d'être traitée comme une proposition
+		checkDeadline(game)
+		checkIdle(game, manager.config.IdleTimeout)
+		if game.Status == "ongoing" && r.Method == http.MethodPost {
+			validToken = playTurn(r, game, lang)
+		}
+	})
+
+	if !validToken {
+		http.Error(w, "Invalid CSRF Token", http.StatusForbidden)
+		return
+	}
+
 	// Si la partie est terminée, rediriger vers la page de fin
-	if game.Status != "ongoing" {
-		http.Redirect(w, r, "/end", http.StatusSeeOther)
+	if view.Status != "ongoing" {
+		http.Redirect(w, r, gameURL("/end", &view), http.StatusSeeOther)
 		return
 	}
 
-	if r.Method == http.MethodPost {
-		// Vérifier le token CSRF
-		csrfToken := r.FormValue("csrf_token")
-		if csrfToken != game.CSRFToken {
-			http.Error(w, "Invalid CSRF Token", http.StatusForbidden)
-			return
+	// Afficher la page de jeu avec l'état actuel
+	view.SortGuesses = sortGuessesPreference(w, r)
+	err := renderTemplate(w, "game.html", &view)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
+
+// Applique à la partie l'action envoyée par le formulaire de jeu. Retourne
+// false si le token CSRF est invalide.
+// Doit être appelée avec le verrou des parties.
+func playTurn(r *http.Request, game *Game, lang string) bool {
+	// Vérifier le token CSRF
+	if r.FormValue("csrf_token") != game.CSRFToken {
+		return false
+	}
+	previousActivity := game.LastActivity
+	game.LastActivity = time.Now()
+	game.Lang = lang
+
+	switch r.FormValue("action") {
+	case "forfeit":
+		forfeitGame(game)
+	case "undo":
+		applyUndo(game)
+	case "peek":
+		applyPeek(game)
+	case "hint":
+		applyHint(game, r.FormValue("kind"))
+	case "freeze":
+		applyFreeze(game)
+	default:
+		// Une proposition trop rapide n'est pas prise en compte
+		if guessTooFast(game, previousActivity, game.LastActivity, manager.config.MinGuessInterval) {
+			return true
 		}
 
-		action := r.FormValue("action")
-		if action == "hint" {
-			if game.HintsUsed >= maxHints {
-				game.Message = "Vous avez atteint le nombre maximum d'indices."
-				game.MessageType = "error"
-				goto render
-			}
-			if game.AttemptsLeft <= 0 {
-				game.Message = "Vous n'avez plus de tentatives pour demander un indice."
-				game.MessageType = "error"
-				goto render
-			}
-			provideHint(game)
-			game.AttemptsLeft-- // Déduire une tentative pour utiliser un indice
+		// Gestion des devinettes
+		guess := strings.TrimSpace(strings.ToLower(r.FormValue("guess")))
+		applyGuess(game, guess)
+	}
+	return true
+}
 
-			// Vérifier si le jeu est gagné ou perdu
-			if allLettersGuessed(game.Word, game.GuessedLetters) {
-				game.Status = "won"
-				game.Message = "Félicitations ! Vous avez deviné toutes les lettres."
-				game.MessageType = "success"
-			}
-			if game.AttemptsLeft <= 0 && game.Status != "won" {
-				game.Status = "lost"
-				game.Message = "Vous avez perdu. Le mot était : " + game.Word
-				game.MessageType = "error"
-			}
+// Handler pour la page de fin de partie
+func endHandler(w http.ResponseWriter, r *http.Request) {
+	sessionID := getSessionID(r)
+	if sessionID == "" {
+		clearSessionCookie(w, r)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
 
-			// Enregistrer le score si la partie est terminée
-			if game.Status != "ongoing" {
-				saveScore(game)
-			}
+	game, exists := manager.Get(sessionID, r.FormValue("game"))
+	if !exists {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
 
-			gamesMutex.Lock()
-			games[sessionID] = game
-			gamesMutex.Unlock()
+	view := manager.Snapshot(game)
 
-			if game.Status != "ongoing" {
-				http.Redirect(w, r, "/end", http.StatusSeeOther)
-				return
-			}
+	// Si la partie est toujours en cours, rediriger vers la page de jeu
+	if view.Status == "ongoing" {
+		http.Redirect(w, r, gameURL("/game", &view), http.StatusSeeOther)
+		return
+	}
+
+	// Afficher la page de fin de partie
+	err := renderTemplate(w, "end.html", &view)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
+
+// Handler pour le défi du jour : tous les joueurs reçoivent le même mot
+// pour une catégorie donnée pendant la journée
+func dailyHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+
+	if r.Method == http.MethodPost {
+		username := strings.TrimSpace(r.FormValue("username"))
+		category := r.FormValue("category")
+		theme := r.FormValue("theme")
 
-			goto render
+		if username == "" || category == "" || theme == "" {
+			http.Error(w, msg(lang, "required_fields"), http.StatusBadRequest)
+			return
 		}
 
-		// Gestion des devinettes
-		guess := strings.TrimSpace(strings.ToLower(r.FormValue("guess")))
-		if guess == "" || !isAlpha(guess) {
-			game.Message = "Veuillez entrer une lettre ou un mot valide."
-			game.MessageType = "error"
-			goto render
+		username, err := sanitizeUsername(username)
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusBadRequest)
+			return
 		}
 
-		// Vérifier si c'est une lettre ou un mot
-		if len(guess) == 1 {
-			// Lettre
-			if contains(game.GuessedLetters, guess) {
-				game.Message = "Vous avez déjà essayé cette lettre."
-				game.MessageType = "error"
-			} else {
-				game.GuessedLetters = append(game.GuessedLetters, guess)
-				if strings.Contains(game.Word, guess) {
-					game.Message = "Bonne réponse !"
-					game.MessageType = "success"
-				} else {
-					game.AttemptsLeft--
-					game.Message = "Mauvaise réponse."
-					game.MessageType = "error"
-				}
-			}
-		} else {
-			// Mot
-			if guess == game.Word {
-				game.Status = "won"
-				game.Message = "Félicitations ! Vous avez deviné le mot."
-				game.MessageType = "success"
-			} else {
-				game.AttemptsLeft--
-				game.Message = "Mauvaise réponse."
-				game.MessageType = "error"
-			}
+		word, err := manager.DailyWord(category)
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusInternalServerError)
+			return
 		}
 
-		// Vérifier si le joueur a gagné
-		if allLettersGuessed(game.Word, game.GuessedLetters) {
-			game.Status = "won"
-			game.Message = "Félicitations ! Vous avez deviné toutes les lettres."
-			game.MessageType = "success"
+		game := newGame(username, dailyDifficulty, category, theme, word)
+		game.Mode = "daily"
+		game.WordIndex = manager.wordIndex(category, dailyDifficulty, "", word)
+		game.Lang = lang
+		sessionID := getSessionID(r)
+		if sessionID == "" {
+			sessionID = generateSessionID()
 		}
+		manager.NewGame(sessionID, game)
+
+		setSessionCookie(w, r, sessionID)
+
+		http.Redirect(w, r, gameURL("/game", game), http.StatusSeeOther)
+		return
+	}
+
+	// Afficher la page du défi du jour
+	data := struct {
+		Date string
+	}{
+		Date: time.Now().Format("02/01/2006"),
+	}
+	err := renderTemplate(w, "daily.html", data)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
+
+// Handler pour reprendre une partie depuis un autre navigateur grâce à son code
+func resumeHandler(w http.ResponseWriter, r *http.Request) {
+	var message string
+
+	if r.Method == http.MethodPost {
+		code := strings.ToUpper(strings.TrimSpace(r.FormValue("code")))
+
+		var sessionID string
+		var game *Game
+		if code != "" {
+			sessionID, game = manager.FindByResumeCode(code)
+		}
+
+		if game != nil {
+			setSessionCookie(w, r, sessionID)
+			http.Redirect(w, r, gameURL("/game", game), http.StatusSeeOther)
+			return
+		}
+		message = "Code inconnu ou partie expirée."
+	}
+
+	// Afficher le formulaire de reprise
+	err := renderTemplate(w, "resume.html", message)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
+
+// Handler pour la page des scores
+func scoresHandler(w http.ResponseWriter, r *http.Request) {
+	// Lire les scores filtrés et triés selon les paramètres de la requête
+	filters, err := parseScoreFilters(r)
+	if err != nil {
+		http.Error(w, errorText(requestLang(w, r), err), http.StatusBadRequest)
+		return
+	}
+	scores, err := loadScores(filters)
+	if err != nil {
+		http.Error(w, "Impossible de lire les scores.", http.StatusInternalServerError)
+		return
+	}
+
+	// Découper la page demandée après le tri
+	page, size := parsePagination(r)
+	pageScores := paginateScores(scores, page, size)
+
+	data := struct {
+		Scores     []Score
+		Sort       string
+		Filters    scoreFilters
+		Page       int
+		TotalPages int
+		PrevURL    string
+		NextURL    string
+		CSVURL     string
+	}{
+		Scores:     pageScores,
+		Sort:       filters.Sort,
+		Filters:    filters,
+		Page:       page,
+		TotalPages: (len(scores) + size - 1) / size,
+	}
+	if page > 1 {
+		data.PrevURL = scoresPageURL(r, page-1)
+	}
+	if page*size < len(scores) {
+		data.NextURL = scoresPageURL(r, page+1)
+	}
+
+	// L'export CSV reprend les filtres et le tri, mais pas la pagination
+	csvQuery := r.URL.Query()
+	csvQuery.Del("page")
+	csvQuery.Del("size")
+	data.CSVURL = "/scores.csv?" + csvQuery.Encode()
+
+	// Afficher la page des scores
+	err = renderTemplate(w, "scores.html", data)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
+
+// csvFlushEvery est le nombre de lignes CSV écrites entre deux envois au client
+const csvFlushEvery = 100
+
+// Handler pour télécharger les scores au format CSV, avec les mêmes filtres
+// que la page /scores. Les lignes sont envoyées au fur et à mesure.
+func scoresCSVHandler(w http.ResponseWriter, r *http.Request) {
+	filters, err := parseScoreFilters(r)
+	if err != nil {
+		http.Error(w, errorText(requestLang(w, r), err), http.StatusBadRequest)
+		return
+	}
+	scores, err := loadScores(filters)
+	if err != nil {
+		http.Error(w, "Impossible de lire les scores.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="scores.csv"`)
+
+	flusher, _ := w.(http.Flusher)
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"username", "difficulty", "category", "status", "word", "hints_used", "timestamp"})
+	for i, score := range scores {
+		writer.Write([]string{
+			score.Username,
+			score.Difficulty,
+			score.Category,
+			score.Status,
+			score.Word,
+			strconv.Itoa(score.HintsUsed),
+			time.Unix(score.Timestamp, 0).Format(time.RFC3339),
+		})
+		if (i+1)%csvFlushEvery == 0 {
+			writer.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		slog.Error("Erreur d'écriture de l'export CSV", "err", err)
+	}
+}
+
+// Retourne les scores triés par points ou par date décroissante, puis filtrés
+// selon les filtres du leaderboard
+func loadScores(filters scoreFilters) ([]Score, error) {
+	scores, err := readSortedScores(filters.Sort)
+	if err != nil {
+		return nil, err
+	}
+	// Le filtrage conserve l'ordre du tri
+	return filterScores(scores, filters), nil
+}
+
+// Lit la page demandée (à partir de 1) et la taille des pages, plafonnée à maxPageSize
+func parsePagination(r *http.Request) (int, int) {
+	page := parsePositiveInt(r.URL.Query().Get("page"), 1)
+	size := parsePositiveInt(r.URL.Query().Get("size"), defaultPageSize)
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	return page, size
+}
+
+// Retourne la page demandée des scores (à partir de 1). Une page hors limites
+// donne une liste vide.
+func paginateScores(scores []Score, page, size int) []Score {
+	start := (page - 1) * size
+	if start >= len(scores) {
+		return nil
+	}
+	end := start + size
+	if end > len(scores) {
+		end = len(scores)
+	}
+	return scores[start:end]
+}
+
+// Construit l'URL d'une autre page du leaderboard en conservant les filtres
+func scoresPageURL(r *http.Request, page int) string {
+	query := r.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	return "/scores?" + query.Encode()
+}
+
+// Lit un entier strictement positif, ou retourne la valeur par défaut
+func parsePositiveInt(value string, def int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return def
+	}
+	return n
+}
+
+// scoreFilters regroupe les filtres du leaderboard. Une valeur vide signifie « tous ».
+type scoreFilters struct {
+	Category   string
+	Difficulty string
+	Status     string
+	Mode       string
+	Sort       string // "points", sinon par date décroissante
+	From       string // Début de la période (RFC3339, inclus)
+	To         string // Fin de la période (RFC3339, exclue)
+
+	from, to time.Time
+}
+
+// Lit les filtres du leaderboard depuis les paramètres de la requête. Une date
+// de période qui n'est pas au format RFC3339 est une erreur.
+func parseScoreFilters(r *http.Request) (scoreFilters, error) {
+	query := r.URL.Query()
+	filters := scoreFilters{
+		Category:   query.Get("category"),
+		Difficulty: query.Get("difficulty"),
+		Status:     query.Get("status"),
+		Mode:       query.Get("mode"),
+		Sort:       query.Get("sort"),
+		From:       query.Get("from"),
+		To:         query.Get("to"),
+	}
+
+	var err error
+	if filters.From != "" {
+		if filters.from, err = time.Parse(time.RFC3339, filters.From); err != nil {
+			return filters, newMsgError("invalid_date", "from", filters.From)
+		}
+	}
+	if filters.To != "" {
+		if filters.to, err = time.Parse(time.RFC3339, filters.To); err != nil {
+			return filters, newMsgError("invalid_date", "to", filters.To)
+		}
+	}
+	if !filters.from.IsZero() && !filters.to.IsZero() && !filters.from.Before(filters.to) {
+		return filters, newMsgError("invalid_date_range")
+	}
+	return filters, nil
+}
+
+// Indique si au moins un filtre est actif (le tri n'en est pas un)
+func (f scoreFilters) Active() bool {
+	return f.Category != "" || f.Difficulty != "" || f.Status != "" || f.Mode != "" ||
+		f.From != "" || f.To != ""
+}
+
+// Retourne les scores correspondant à tous les filtres actifs.
+// Une valeur inconnue donne simplement une liste vide.
+func filterScores(scores []Score, f scoreFilters) []Score {
+	var filtered []Score
+	for _, score := range scores {
+		if f.Category != "" && score.Category != f.Category {
+			continue
+		}
+		if f.Difficulty != "" && score.Difficulty != f.Difficulty {
+			continue
+		}
+		if f.Status != "" && score.Status != f.Status {
+			continue
+		}
+		if f.Mode != "" && score.Mode != f.Mode {
+			continue
+		}
+		// La période inclut sa date de début mais pas sa date de fin
+		played := time.Unix(score.Timestamp, 0)
+		if !f.from.IsZero() && played.Before(f.from) {
+			continue
+		}
+		if !f.to.IsZero() && !played.Before(f.to) {
+			continue
+		}
+		filtered = append(filtered, score)
+	}
+	return filtered
+}
+
+// Crée une nouvelle partie pour le joueur avec le mot donné
+func newGame(username, difficulty, category, theme, word string) *Game {
+	attempts := startingAttempts(difficulty)
+	now := time.Now()
+	return &Game{
+		Username:       username,
+		Difficulty:     difficulty,
+		Category:       category,
+		Word:           normalizeSpaces(strings.ToLower(word)),
+		WordRating:     scoreWordDifficulty(word),
+		GuessedLetters: []string{},
+		AttemptsLeft:   attempts,
+		MaxAttempts:    attempts,
+		Status:         "ongoing",
+		CreatedAt:      now,
+		LastActivity:   now,
+		LastGuess:      now,
+		Deadline:       now.Add(timeLimit(difficulty)),
+		HintsUsed:      0,
+		MaxHints:       manager.config.MaxHints,
+		MaxFreezes:     maxFreezes,
+		HintPolicy:     defaultHintPolicy,
+		HintCost:       hintCostFor(difficulty),
+		HintStrategy:   defaultHintStrategy,
+		Avatar:         defaultAvatar,
+		Theme:          theme,
+		Mode:           "normal",
+		CSRFToken:      generateCSRFToken(),
+	}
+}
+
+// Retourne le nombre de tentatives au départ pour une difficulté
+func startingAttempts(difficulty string) int {
+	if attempts, ok := attemptsByDifficulty[difficulty]; ok {
+		return attempts
+	}
+	return defaultAttempts
+}
+
+// Lit le nombre d'indices choisi par le joueur (2 par défaut). Les valeurs hors
+// de l'intervalle 0-5 sont refusées et le nombre est plafonné selon la difficulté.
+func parseMaxHints(value, difficulty string) (int, error) {
+	if value == "" {
+		return manager.config.MaxHints, nil
+	}
+	hints, err := strconv.Atoi(value)
+	if err != nil || hints < 0 || hints > maxHintsLimit {
+		return 0, newMsgError("hints_out_of_range", maxHintsLimit)
+	}
+	if limit, ok := hintsCapByDifficulty[difficulty]; ok && hints > limit {
+		hints = limit
+	}
+	return hints, nil
+}
+
+// Retourne le coût d'un indice en tentatives pour une difficulté
+func hintCostFor(difficulty string) int {
+	if cost, ok := hintCostByDifficulty[difficulty]; ok {
+		return cost
+	}
+	return defaultHintCost
+}
+
+// Lit le coût d'un indice choisi par le joueur, en tentatives (celui de la
+// difficulté par défaut). Les valeurs hors de l'intervalle 0-2 sont refusées.
+func parseHintCost(value, difficulty string) (int, error) {
+	if value == "" {
+		return hintCostFor(difficulty), nil
+	}
+	cost, err := strconv.Atoi(value)
+	if err != nil || cost < 0 || cost > maxHintCost {
+		return 0, newMsgError("hint_cost_out_of_range", maxHintCost)
+	}
+	return cost, nil
+}
+
+// Lit la politique d'indice choisie par le joueur ("costly" par défaut)
+func parseHintPolicy(value string) (string, error) {
+	if value == "" {
+		return defaultHintPolicy, nil
+	}
+	for _, policy := range hintPolicies {
+		if value == policy {
+			return policy, nil
+		}
+	}
+	return "", newMsgError("unknown_hint_policy", value)
+}
+
+// Lit la stratégie d'indice choisie par le joueur ("first" par défaut)
+func parseHintStrategy(value string) (string, error) {
+	if value == "" {
+		return defaultHintStrategy, nil
+	}
+	for _, strategy := range hintStrategies {
+		if value == strategy {
+			return strategy, nil
+		}
+	}
+	return "", newMsgError("unknown_hint_strategy", value)
+}
+
+// Lit l'avatar choisi par le joueur ("neutral" par défaut)
+func parseAvatar(value string) (string, error) {
+	if value == "" {
+		return defaultAvatar, nil
+	}
+	if _, ok := avatars[value]; !ok {
+		return "", newMsgError("unknown_avatar", value)
+	}
+	return value, nil
+}
+
+// Retourne l'emoji d'un avatar. Les scores enregistrés avant l'ajout des
+// avatars ont l'avatar neutre.
+func avatarEmoji(name string) string {
+	if emoji, ok := avatars[name]; ok {
+		return emoji
+	}
+	return avatars[defaultAvatar]
+}
+
+// Construit l'URL d'une page pour une partie donnée de la session
+func gameURL(path string, game *Game) string {
+	return path + "?game=" + url.QueryEscape(game.ID)
+}
+
+// Indique si une proposition arrive moins de interval (Config.MinGuessInterval)
+// après l'action précédente dans un mode compétitif. Zéro désactive le
+// contrôle. Le message d'erreur est alors placé sur la partie.
+func guessTooFast(game *Game, previous, now time.Time, interval time.Duration) bool {
+	if interval <= 0 || !contains(competitiveModes, game.Mode) {
+		return false
+	}
+	if now.Sub(previous) >= interval {
+		return false
+	}
+	game.Message = msg(game.Lang, "guess_too_fast")
+	game.MessageType = "error"
+	return true
+}
+
+// Applique une proposition (lettre ou mot) à la partie.
+// Retourne false si la proposition est invalide et n'a pas été prise en compte.
+func applyGuess(game *Game, guess string) bool {
+	guess = normalizeSpaces(guess)
+	if !isValidGuess(guess) {
+		game.Message = msg(game.Lang, "invalid_guess")
+		game.MessageType = "error"
+		return false
+	}
+
+	// Un mot de longueur différente est une faute de frappe : pas de tentative perdue
+	guessLength := utf8.RuneCountInString(guess)
+	wordLength := utf8.RuneCountInString(game.Word)
+	if guessLength > 1 && guessLength != wordLength {
+		game.Message = msg(game.Lang, "word_length", wordLength)
+		game.MessageType = "error"
+		return false
+	}
+
+	// En mode mot entier, pas de pêche aux lettres
+	if guessLength == 1 && game.WordOnlyMode {
+		game.Message = msg(game.Lang, "word_only_letter")
+		game.MessageType = "error"
+		return false
+	}
+
+	// Vérifier si c'est une lettre ou un mot (en runes, pour les lettres accentuées)
+	if guessLength == 1 {
+		// Lettre
+		if game.letterGuessed(guess) {
+			game.Message = msg(game.Lang, "letter_already_tried")
+			game.MessageType = "error"
+		} else {
+			game.LastGuess = time.Now()
+			game.GuessedLetters = append(game.GuessedLetters, guess)
+			recordLetterGuess(game.Category, guess, len(game.GuessedLetters) == 1)
+			recordGuess(game, guess, game.hasLetter(guess))
+			if game.hasLetter(guess) {
+				game.Message = msg(game.Lang, "correct_guess")
+				game.MessageType = "success"
+			} else {
+				loseAttempt(game)
+				game.WrongGuesses++
+				game.Message = msg(game.Lang, "wrong_guess")
+				game.MessageType = "error"
+			}
+		}
+	} else {
+		// Mot
+		game.LastGuess = time.Now()
+		correct := sameWord(guess, normalizeSpaces(game.Word), game.AccentInsensitive)
+		recordGuess(game, guess, correct)
+		if correct {
+			game.Status = "won"
+			game.Message = msg(game.Lang, "word_guessed")
+			game.MessageType = "success"
+		} else {
+			loseAttempt(game)
+			game.WrongGuesses++
+			game.Message = msg(game.Lang, "wrong_guess")
+			// En mode détente, indiquer la proximité sans révéler le mot
+			if game.CasualMode && game.Mode != "hardcore" {
+				game.Message += " " + closenessMessage(game.Lang, guess, normalizeSpaces(game.Word))
+			}
+			game.MessageType = "error"
+		}
+	}
+
+	checkGameEnd(game)
+	return true
+}
+
+// Retourne la distance de Levenshtein entre la proposition et le mot, en
+// runes : le nombre minimal d'insertions, suppressions ou substitutions de
+// lettres pour passer de l'un à l'autre
+func wordCloseness(guess, target string) int {
+	a := []rune(guess)
+	b := []rune(target)
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// Traduit la distance d'une proposition en palier (très proche, proche ou
+// loin), relatif à la longueur du mot pour ne rien dévoiler de plus
+func closenessMessage(lang, guess, target string) string {
+	distance := wordCloseness(guess, target)
+	length := utf8.RuneCountInString(target)
+	switch {
+	case distance <= 1 || distance*5 <= length:
+		return msg(lang, "closeness_very_close")
+	case distance*2 <= length:
+		return msg(lang, "closeness_close")
+	}
+	return msg(lang, "closeness_far")
+}
+
+// Applique une demande d'indice selon la politique de la partie.
+// Retourne false si l'indice a été refusé.
+func applyHint(game *Game, kind string) bool {
+	if rejectInHardcore(game) {
+		return false
+	}
+	if game.HintsRemaining() == 0 {
+		game.Message = msg(game.Lang, "max_hints_reached")
+		game.MessageType = "error"
+		return false
+	}
+
+	// L'indice de position remplace la lettre révélée selon la stratégie
+	reveal := func() { provideHint(game, game.HintStrategy) }
+	if kind == "position" {
+		reveal = func() { providePositionHint(game) }
+	}
+
+	cost := game.HintAttempts()
+	if cost > 0 && game.AttemptsLeft <= 0 {
+		game.Message = msg(game.Lang, "no_attempts_for_hint")
+		game.MessageType = "error"
+		return false
+	}
+
+	if game.HintPolicy == "category-clue" && kind != "position" {
+		if game.HintClue != "" {
+			game.Message = msg(game.Lang, "category_clue_given")
+			game.MessageType = "error"
+			return false
+		}
+		provideCategoryClue(game)
+	} else {
+		reveal()
+	}
+
+	// Déduire le coût de l'indice, sans descendre sous zéro tentative
+	for i := 0; i < cost && game.AttemptsLeft > 0; i++ {
+		loseAttempt(game)
+	}
+
+	// Vérifier si le jeu est gagné ou perdu
+	checkGameEnd(game)
+	return true
+}
+
+// Applique le gel du chrono : une lettre est révélée comme par un indice, sans
+// compter parmi les indices, et l'heure limite est repoussée de freezeBonus.
+// L'heure limite ne dépasse jamais le temps imparti au départ compté depuis
+// maintenant. Retourne false si le gel est refusé.
+func applyFreeze(game *Game) bool {
+	if rejectInHardcore(game) {
+		return false
+	}
+	if game.Deadline.IsZero() {
+		game.Message = msg(game.Lang, "freeze_untimed")
+		game.MessageType = "error"
+		return false
+	}
+	if game.FreezesRemaining() == 0 {
+		game.Message = msg(game.Lang, "freeze_none_left")
+		game.MessageType = "error"
+		return false
+	}
+
+	letter := revealLetter(game, game.HintStrategy)
+	if letter == "" {
+		return false
+	}
+
+	deadline := game.Deadline.Add(freezeBonus)
+	if limit := time.Now().Add(timeLimit(game.Difficulty)); deadline.After(limit) {
+		deadline = limit
+	}
+	extension := 0
+	if deadline.After(game.Deadline) {
+		extension = int(deadline.Sub(game.Deadline).Seconds())
+		game.Deadline = deadline
+	}
+	game.FreezesUsed++
+	game.FrozenSeconds += extension
+	recordFreeze(game, letter, extension)
+	game.Message = msg(game.Lang, "freeze_used", extension)
+	game.MessageType = "success"
+
+	checkGameEnd(game)
+	return true
+}
+
+// Annule la dernière lettre proposée, une seule fois par partie. Si la lettre
+// était mauvaise, la tentative perdue est rendue. Retourne false si
+// l'annulation n'est pas possible.
+func applyUndo(game *Game) bool {
+	if rejectInHardcore(game) {
+		return false
+	}
+	if game.UndoUsed {
+		game.Message = msg(game.Lang, "undo_used")
+		game.MessageType = "error"
+		return false
+	}
+	if len(game.GuessedLetters) == 0 {
+		game.Message = msg(game.Lang, "undo_nothing")
+		game.MessageType = "error"
+		return false
+	}
+
+	last := game.GuessedLetters[len(game.GuessedLetters)-1]
+	game.GuessedLetters = game.GuessedLetters[:len(game.GuessedLetters)-1]
+	game.UndoUsed = true
+	recordUndo(game, last)
+
+	// Retirer une lettre ne peut que masquer le mot : la partie reste en cours
+	if !game.hasLetter(last) {
+		if !game.Practice {
+			game.AttemptsLeft++
+		}
+		game.WrongGuesses--
+		game.Message = msg(game.Lang, "undo_refunded", last)
+	} else {
+		game.Message = msg(game.Lang, "undo_done", last)
+	}
+	game.MessageType = "success"
+	return true
+}
+
+// Refuse les aides (indice, gel du chrono, annulation, coup d'œil) en mode hardcore.
+// Retourne true si l'action est refusée.
+func rejectInHardcore(game *Game) bool {
+	if game.Mode != "hardcore" {
+		return false
+	}
+	game.Message = msg(game.Lang, "hardcore_disabled")
+	game.MessageType = "error"
+	return true
+}
+
+// Passe la partie en mode hardcore : tentatives réduites et aucun indice
+func makeHardcore(game *Game) {
+	game.Mode = "hardcore"
+	game.AttemptsLeft = hardcoreAttempts
+	game.MaxAttempts = hardcoreAttempts
+	game.MaxHints = 0
+	game.MaxFreezes = 0
+}
+
+// Passe la partie en mode entraînement : les erreurs sont comptées sans faire
+// perdre de tentative et il n'y a pas de limite de temps
+func makePractice(game *Game) {
+	game.Practice = true
+	game.Deadline = time.Time{}
+	game.MaxFreezes = 0 // Sans chrono, rien à geler
+}
+
+// Affiche le thème de la partie dès le premier coup. Les modes compétitifs et
+// le mode hardcore, sans aucune aide, n'y ont pas droit.
+func enableThemeClue(game *Game) {
+	if game.Mode == "hardcore" || contains(competitiveModes, game.Mode) {
+		return
+	}
+	game.ShowThemeClue = true
+}
+
+// Active la correspondance insensible aux accents : « e » révèle aussi « é »,
+// « è »... et inversement. Le mode hardcore reste strict.
+func enableAccentInsensitive(game *Game) {
+	if game.Mode == "hardcore" {
+		return
+	}
+	game.AccentInsensitive = true
+}
+
+// Retire une tentative, sauf en mode entraînement où seules les erreurs sont comptées
+func loseAttempt(game *Game) {
+	if !game.Practice {
+		game.AttemptsLeft--
+	}
+}
+
+// Passe la partie en mode mot entier : chaque proposition doit être le mot
+// complet. Les indices révéleraient des lettres, ils sont donc désactivés.
+func makeWordOnly(game *Game) {
+	game.WordOnlyMode = true
+	game.MaxHints = 0
+	game.MaxFreezes = 0
+}
+
+// Applique le coup d'œil gratuit, utilisable une seule fois par partie.
+// Retourne false s'il a déjà été utilisé.
+func applyPeek(game *Game) bool {
+	if rejectInHardcore(game) {
+		return false
+	}
+	if game.PeekUsed {
+		game.Message = msg(game.Lang, "peek_used")
+		game.MessageType = "error"
+		return false
+	}
+
+	game.PeekUsed = true
+	game.Message = peekInfo(game)
+	game.MessageType = "success"
+	return true
+}
+
+// Décrit les lettres restant à trouver sans les révéler : leur nombre et une
+// silhouette du mot où chaque lettre inconnue est remplacée par un numéro,
+// identique pour toutes les occurrences d'une même lettre.
+func peekInfo(game *Game) string {
+	numbers := make(map[string]int)
+	silhouette := make([]string, 0, len(game.Word))
+	for _, c := range game.Word {
+		letter := string(c)
+		if isSeparator(c) || game.letterGuessed(letter) {
+			silhouette = append(silhouette, letter)
+			continue
+		}
+		// Les formes finales et, si demandé, les variantes accentuées d'une
+		// lettre partagent son numéro
+		key := finalLetterForms.Replace(letter)
+		if game.AccentInsensitive {
+			key = accentFolder.Replace(key)
+		}
+		if _, seen := numbers[key]; !seen {
+			numbers[key] = len(numbers) + 1
+		}
+		silhouette = append(silhouette, strconv.Itoa(numbers[key]))
+	}
+
+	return msg(game.Lang, "peek_info", len(numbers), strings.Join(silhouette, " "))
+}
+
+// Vérifie si la partie est gagnée ou perdue et enregistre le score le cas échéant
+func checkGameEnd(game *Game) {
+	// Vérifier si le joueur a gagné
+	if allLettersGuessed(game.Word, game.GuessedLetters, game.AccentInsensitive) {
+		game.Status = "won"
+		game.Message = msg(game.Lang, "all_letters_guessed")
+		game.MessageType = "success"
+	}
+
+	// Vérifier si le joueur a perdu
+	if game.AttemptsLeft <= 0 && game.Status != "won" {
+		game.Status = "lost"
+		game.Message = msg(game.Lang, "game_lost", game.Word)
+		game.MessageType = "error"
+	}
+
+	// En mode course, le premier gagnant termine le salon
+	if game.Status == "won" && game.RoomCode != "" {
+		finishRoom(game)
+	}
+
+	// Enregistrer le score si la partie est terminée
+	if game.Status != "ongoing" {
+		finishGame(game)
+	}
+}
+
+// Retourne le temps imparti pour une difficulté
+func timeLimit(difficulty string) time.Duration {
+	if limit, ok := timeLimitByDifficulty[difficulty]; ok {
+		return limit
+	}
+	return defaultTimeLimit
+}
+
+// SecondsLeft retourne le nombre de secondes restantes avant l'heure limite,
+// ou -1 si la partie n'a pas de limite (parties créées avant le minuteur)
+func (g *Game) SecondsLeft() int {
+	if g.Deadline.IsZero() {
+		return -1
+	}
+	left := int(time.Until(g.Deadline).Seconds())
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// Termine la partie en défaite si l'heure limite est dépassée.
+// Retourne true si la partie vient d'expirer.
+func checkDeadline(game *Game) bool {
+	if game.Status != "ongoing" || game.Deadline.IsZero() || time.Now().Before(game.Deadline) {
+		return false
+	}
+
+	game.Status = "lost"
+	game.TimedOut = true
+	game.Message = msg(game.Lang, "game_timeout", game.Word)
+	game.MessageType = "error"
+	finishGame(game)
+	return true
+}
+
+// Abandonne d'office une partie chronométrée restée sans proposition plus
+// longtemps que timeout (Config.IdleTimeout), pour qu'un joueur ne puisse pas
+// mettre le chrono en pause indéfiniment. Zéro désactive le contrôle.
+// Retourne true si la partie vient d'être abandonnée.
+func checkIdle(game *Game, timeout time.Duration) bool {
+	if !idleExpired(game, timeout) {
+		return false
+	}
+	markIdleForfeited(game)
+	finishGame(game)
+	return true
+}
+
+// Indique si une partie chronométrée en cours est restée sans proposition
+// plus longtemps que timeout. Les indices, coups d'œil et annulations ne
+// comptent pas. Les parties sauvegardées avant l'ajout de LastGuess se basent
+// sur leur dernière activité, puis sur leur date de création.
+func idleExpired(game *Game, timeout time.Duration) bool {
+	if game.Status != "ongoing" || timeout <= 0 || game.Deadline.IsZero() {
+		return false
+	}
+	last := game.LastGuess
+	if last.IsZero() {
+		last = game.LastActivity
+	}
+	if last.IsZero() {
+		last = game.CreatedAt
+	}
+	return time.Since(last) > timeout
+}
 
-		// Vérifier si le joueur a perdu
-		if game.AttemptsLeft <= 0 && game.Status != "won" {
-			game.Status = "lost"
-			game.Message = "Vous avez perdu. Le mot était : " + game.Word
-			game.MessageType = "error"
-		}
+// Marque la partie comme abandonnée faute de proposition, sans enregistrer
+// son score (voir finishGame)
+func markIdleForfeited(game *Game) {
+	markForfeited(game)
+	game.Message = msg(game.Lang, "game_idle_forfeited", game.Word)
+}
 
-		// Enregistrer le score si la partie est terminée
-		if game.Status != "ongoing" {
-			saveScore(game)
+// Abandonne d'office les parties inactives, même si le joueur ne revient pas
+func sweepIdleGames() {
+	for {
+		time.Sleep(idleSweepInterval)
+		if manager.config.IdleTimeout > 0 {
+			manager.ForfeitIdle()
 		}
+	}
+}
 
-		gamesMutex.Lock()
-		games[sessionID] = game
-		gamesMutex.Unlock()
+// Abandonne la partie : elle est perdue et le score est marqué comme un abandon
+func forfeitGame(game *Game) {
+	markForfeited(game)
+	finishGame(game)
+}
 
-		if game.Status != "ongoing" {
-			http.Redirect(w, r, "/end", http.StatusSeeOther)
-			return
-		}
+// Marque la partie comme abandonnée, sans enregistrer son score
+func markForfeited(game *Game) {
+	game.Status = "lost"
+	game.Forfeited = true
+	game.Message = msg(game.Lang, "game_forfeited", game.Word)
+	game.MessageType = "error"
+}
 
+// Enregistre la durée d'une partie terminée et sauvegarde son score
+func finishGame(game *Game) {
+	game.DurationSeconds = int(time.Since(game.CreatedAt).Seconds())
+	slog.Info("Partie terminée",
+		"event", "game_finished",
+		"username", game.Username,
+		"category", game.Category,
+		"difficulty", game.Difficulty,
+		"mode", game.Mode,
+		"status", game.Status,
+		"forfeited", game.Forfeited,
+		"practice", game.Practice,
+		"duration_seconds", game.DurationSeconds)
+	recordGameResult(game.Category, game.Status)
+	// Les parties d'entraînement restent hors du leaderboard
+	if game.Practice {
+		return
 	}
+	saveScore(game)
+}
 
-render:
-	// Afficher la page de jeu avec l'état actuel
-	err := templates.ExecuteTemplate(w, "game.html", game)
+// Retourne une copie de toutes les entrées du fichier des scores, lues depuis
+// le cache quand aucun score n'a été enregistré depuis la dernière lecture
+func readScores() ([]Score, error) {
+	scoresMutex.Lock()
+	defer scoresMutex.Unlock()
+
+	scores, err := cachedScores()
 	if err != nil {
-		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+		return nil, err
 	}
+	return append([]Score(nil), scores...), nil
 }
 
-// Handler pour la page de fin de partie
-func endHandler(w http.ResponseWriter, r *http.Request) {
-	sessionID := getSessionID(r)
-	if sessionID == "" {
-		http.Redirect(w, r, "/", http.StatusSeeOther)
-		return
-	}
+// Retourne le score enregistré avec cet identifiant. Le booléen est faux si
+// aucun score ne correspond.
+func findScore(id string) (Score, bool, error) {
+	scoresMutex.Lock()
+	defer scoresMutex.Unlock()
 
-	gamesMutex.Lock()
-	game, exists := games[sessionID]
-	gamesMutex.Unlock()
+	scores, err := cachedScores()
+	if err != nil {
+		return Score{}, false, err
+	}
+	for _, score := range scores {
+		if score.ID == id {
+			return score, true, nil
+		}
+	}
+	return Score{}, false, nil
+}
 
-	if !exists {
-		http.Redirect(w, r, "/", http.StatusSeeOther)
-		return
+// Génère un identifiant de score qui n'est pas déjà utilisé dans le fichier
+// des scores. Doit être appelée avec scoresMutex verrouillé.
+func generateScoreID() string {
+	scores, err := cachedScores()
+	if err != nil {
+		slog.Error("Erreur de lecture des scores", "err", err)
+	}
+	for {
+		id := generateShortCode(scoreIDLength)
+		used := false
+		for _, score := range scores {
+			if score.ID == id {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return id
+		}
 	}
+}
 
-	// Si la partie est toujours en cours, rediriger vers la page de jeu
-	if game.Status == "ongoing" {
-		http.Redirect(w, r, "/game", http.StatusSeeOther)
-		return
+// Retourne les scores triés par points ("points") ou par date décroissante.
+// Le tri n'est refait qu'après l'enregistrement d'un nouveau score ; la
+// liste renvoyée est partagée et ne doit pas être modifiée.
+func readSortedScores(sortBy string) ([]Score, error) {
+	if sortBy != "points" {
+		sortBy = "date"
 	}
 
-	// Afficher la page de fin de partie
-	err := templates.ExecuteTemplate(w, "end.html", game)
+	scoresMutex.Lock()
+	defer scoresMutex.Unlock()
+
+	scores, err := cachedScores()
 	if err != nil {
-		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+		return nil, err
+	}
+	if sorted, ok := sortedScoresCache[sortBy]; ok {
+		return sorted, nil
+	}
+
+	// Parcourir le fichier à rebours pour qu'à égalité, le score le plus
+	// récemment enregistré passe en premier
+	sorted := make([]Score, 0, len(scores))
+	for i := len(scores) - 1; i >= 0; i-- {
+		sorted = append(sorted, scores[i])
 	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sortBy == "points" && sorted[i].Points != sorted[j].Points {
+			return sorted[i].Points > sorted[j].Points
+		}
+		return sorted[i].Timestamp > sorted[j].Timestamp
+	})
+	sortedScoresCache[sortBy] = sorted
+	return sorted, nil
 }
 
-// Handler pour la page des scores
-func scoresHandler(w http.ResponseWriter, r *http.Request) {
-	// Lire les scores depuis le fichier
-	scoresData, err := os.ReadFile(scoreFilePath)
+// Invalide le cache des scores après une écriture dans le fichier.
+// Doit être appelée avec scoresMutex verrouillé.
+func invalidateScoresCache() {
+	scoresCache = nil
+	scoresCacheValid = false
+	sortedScoresCache = map[string][]Score{}
+}
+
+// Lit toutes les entrées du fichier des scores (une entrée JSON par ligne),
+// ou les reprend du cache s'il est à jour. Les lignes invalides sont ignorées
+// et un fichier absent donne une liste vide.
+// Doit être appelée avec scoresMutex verrouillé.
+func cachedScores() ([]Score, error) {
+	if scoresCacheValid {
+		return scoresCache, nil
+	}
+
+	scoresData, err := os.ReadFile(manager.config.ScoresPath)
 	if err != nil {
-		http.Error(w, "Impossible de lire les scores.", http.StatusInternalServerError)
-		return
+		if os.IsNotExist(err) {
+			scoresCacheValid = true
+			return nil, nil
+		}
+		return nil, err
 	}
 
 	var scores []Score
@@ -383,82 +1963,388 @@ func scoresHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		var score Score
 		if err := json.Unmarshal([]byte(line), &score); err != nil {
-			log.Println("Erreur de parsing du score:", err)
+			slog.Error("Erreur de parsing du score", "err", err)
 			continue
 		}
+		migrateScore(&score)
 		scores = append(scores, score)
 	}
 
-	// Trier les scores par date décroissante
-	sort.Slice(scores, func(i, j int) bool {
-		return scores[i].Timestamp > scores[j].Timestamp
-	})
+	scoresCache = scores
+	scoresCacheValid = true
+	return scores, nil
+}
 
-	data := struct {
-		Scores []Score
-	}{
-		Scores: scores,
+// Met à niveau un score lu depuis le fichier vers la version actuelle, en
+// complétant les champs absents des anciennes lignes. Une ligne d'une version
+// plus récente est gardée telle quelle.
+func migrateScore(score *Score) {
+	if score.Version == 0 {
+		score.Version = 1
+	}
+	if score.Version >= scoreVersion {
+		return
 	}
 
-	// Afficher la page des scores
-	err = templates.ExecuteTemplate(w, "scores.html", data)
-	if err != nil {
-		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	// Version 1 : mode, catégorie d'origine, difficulté du mot et détail des
+	// points pouvaient manquer
+	if score.Version < 2 {
+		if score.Mode == "" {
+			score.Mode = "normal"
+		}
+		if score.SourceCategory == "" && score.Category != randomCategory {
+			score.SourceCategory = score.Category
+		}
+		if score.WordRating == 0 {
+			score.WordRating = scoreWordDifficulty(score.Word)
+		}
+		if score.BasePoints == 0 {
+			score.BasePoints = score.Points - score.BlitzBonus
+		}
+	}
+	// Version 2 : le nombre de mauvaises propositions n'était pas enregistré,
+	// il est recompté depuis l'historique
+	if score.Version < 3 {
+		score.WrongGuesses = countWrongGuesses(score.Guesses)
 	}
+	score.Version = scoreVersion
 }
 
-// Sélectionne un mot aléatoire basé sur le niveau de difficulté et la catégorie
-func getRandomWord(difficulty, category string) string {
-	categoryWords, exists := wordsByCategory[category]
-	if !exists {
-		return "erreur"
+// Erreurs retournées quand aucune liste ne contient de mot pour la catégorie
+// et la difficulté demandées, ou pour le défi du jour
+var (
+	errNoWord      = newMsgError("no_word_available")
+	errNoDailyWord = newMsgError("no_daily_word")
+)
+
+// RandomWord sélectionne un mot aléatoire basé sur le niveau de difficulté et la catégorie
+// Si biased est vrai, la sélection favorise les mots dont la difficulté
+// calculée correspond au niveau choisi. Pour la catégorie "custom", le mot est
+// tiré de la liste personnalisée listID. Retourne aussi la catégorie d'origine
+// du mot, qui diffère de category pour la catégorie "random", et le thème de
+// la sous-liste d'où il a été tiré ("" pour la liste principale). Sans mot
+// disponible, retourne errNoWord.
+func (m *GameManager) RandomWord(difficulty, category, listID string, biased bool) (string, string, string, error) {
+	if category == customCategory {
+		word, err := getCustomWord(listID)
+		return word, customCategory, "", err
+	}
+
+	m.wordsMu.RLock()
+	defer m.wordsMu.RUnlock()
+
+	var pool wordPool
+	if category == randomCategory {
+		// Toutes les vraies catégories sont réunies, sous-listes comprises
+		for _, source := range wordCategories {
+			if source != randomCategory {
+				m.addToPool(&pool, source, difficulty)
+			}
+		}
+	} else {
+		if _, exists := m.words[category]; !exists {
+			return "", "", "", errNoWord
+		}
+		m.addToPool(&pool, category, difficulty)
+	}
+	return pool.pick(difficulty, biased)
+}
+
+// Tire un mot au hasard en pondérant par sa difficulté calculée : en difficile
+// les mots les plus durs sont favorisés, en facile les plus simples, et le
+// niveau moyen reste uniforme. Retourne l'indice du mot tiré.
+func pickWeightedIndex(words []string, difficulty string) int {
+	scores := make([]int, len(words))
+	maxScore := 0
+	for i, word := range words {
+		scores[i] = scoreWordDifficulty(word)
+		if scores[i] > maxScore {
+			maxScore = scores[i]
+		}
+	}
+
+	weights := make([]int, len(words))
+	total := 0
+	for i, score := range scores {
+		switch difficulty {
+		case "hard":
+			weights[i] = score + 1
+		case "easy":
+			weights[i] = maxScore - score + 1
+		default:
+			weights[i] = 1
+		}
+		total += weights[i]
+	}
+
+	n := rand.Intn(total)
+	for i, weight := range weights {
+		if n < weight {
+			return i
+		}
+		n -= weight
+	}
+	return len(words) - 1
+}
+
+// Lettres rares en français, plus difficiles à deviner
+const rareLetters = "kwxyzjq"
+
+// Calcule un score de difficulté pour un mot : plus il est élevé, plus le mot
+// est difficile. Le score tient compte de la longueur, de la proportion de
+// lettres répétées et de la présence de lettres rares (w, x, z...).
+func scoreWordDifficulty(word string) int {
+	letters := 0
+	unique := make(map[rune]bool)
+	rare := make(map[rune]bool)
+	for _, c := range strings.ToLower(word) {
+		if isSeparator(c) {
+			continue
+		}
+		letters++
+		unique[c] = true
+		if strings.ContainsRune(rareLetters, c) {
+			rare[c] = true
+		}
 	}
-	words, exists := categoryWords[difficulty]
-	if !exists || len(words) == 0 {
-		return "erreur"
+	if letters == 0 {
+		return 0
 	}
-	return words[rand.Intn(len(words))]
+
+	score := letters
+	score += (letters - len(unique)) * 10 / letters // Proportion de lettres répétées
+	score += len(rare) * 3
+	return score
+}
+
+// Seuils de scoreWordDifficulty séparant les mots faciles, moyens et difficiles
+const (
+	easyWordRatingMax   = 6
+	mediumWordRatingMax = 10
+)
+
+// Traduit la difficulté calculée d'un mot en libellé pour l'écran de fin
+func wordDifficultyLabel(rating int) string {
+	switch {
+	case rating <= easyWordRatingMax:
+		return "facile"
+	case rating <= mediumWordRatingMax:
+		return "moyen"
+	}
+	return "difficile"
+}
+
+// DailyWord retourne le mot du jour pour une catégorie. Le premier tirage de
+// la journée est enregistré dans l'archive et repris aux appels suivants, pour
+// que l'ajout, l'import ou le rechargement des listes ne change pas le mot en
+// cours de journée. Le générateur aléatoire est initialisé à partir de la date
+// (YYYYMMDD) et de la catégorie, ce qui rend le tirage identique pour tous les
+// joueurs.
+func (m *GameManager) DailyWord(category string) (string, error) {
+	now := time.Now()
+	if word, ok := archivedDailyWord(now, category); ok {
+		return word, nil
+	}
+
+	m.wordsMu.RLock()
+	words := m.words[category][dailyDifficulty]
+	if len(words) == 0 {
+		m.wordsMu.RUnlock()
+		return "", errNoDailyWord
+	}
+	h := fnv.New64a()
+	h.Write([]byte(now.Format("20060102") + ":" + category))
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+	word := words[rng.Intn(len(words))]
+	m.wordsMu.RUnlock()
+
+	return recordDailyWord(now, category, word), nil
 }
 
 // Génère un ID de session unique basé sur des bytes aléatoires
 func generateSessionID() string {
 	bytes := make([]byte, 16)
 	if _, err := crand.Read(bytes); err != nil {
-		log.Println("Erreur lors de la génération du session ID:", err)
+		slog.Error("Erreur lors de la génération du session ID", "err", err)
 		return strconv.FormatInt(time.Now().UnixNano(), 10)
 	}
 	return hex.EncodeToString(bytes)
 }
 
+// Alphabet des codes courts, sans caractères ambigus (0/O, 1/I)
+const shortCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Génère un code court, facile à saisir, de la longueur donnée
+func generateShortCode(length int) string {
+	bytes := make([]byte, length)
+	if _, err := crand.Read(bytes); err != nil {
+		slog.Error("Erreur lors de la génération du code", "err", err)
+		// Repli sur l'ID de session en cas d'erreur du générateur
+		return strings.ToUpper(generateSessionID()[:length])
+	}
+	code := make([]byte, length)
+	for i, b := range bytes {
+		code[i] = shortCodeAlphabet[int(b)%len(shortCodeAlphabet)]
+	}
+	return string(code)
+}
+
+// Génère un ID de partie unique parmi les parties de la session.
+// Doit être appelée avec le verrou des parties.
+func generateGameID(sessionGames map[string]*Game) string {
+	for {
+		id := generateShortCode(gameIDLength)
+		if _, exists := sessionGames[id]; !exists {
+			return id
+		}
+	}
+}
+
 // Génère un token CSRF
 func generateCSRFToken() string {
 	bytes := make([]byte, 16)
 	if _, err := crand.Read(bytes); err != nil {
-		log.Println("Erreur lors de la génération du CSRF Token:", err)
+		slog.Error("Erreur lors de la génération du CSRF Token", "err", err)
 		return strconv.FormatInt(time.Now().UnixNano(), 10)
 	}
 	return hex.EncodeToString(bytes)
 }
 
-// Récupère l'ID de session depuis les cookies
+// Envoie le cookie de session au client
+func setSessionCookie(w http.ResponseWriter, r *http.Request, sessionID string) {
+	http.SetCookie(w, newSessionCookie(r, sessionID))
+}
+
+// Construit le cookie de session. Il n'est marqué Secure que si la requête
+// est arrivée en HTTPS, directement ou derrière un proxy qui l'indique.
+func newSessionCookie(r *http.Request, sessionID string) *http.Cookie {
+	secure := r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+	return &http.Cookie{
+		Name:     "session_id",
+		Value:    sessionID,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// Supprime le cookie de session du client, s'il en a envoyé un
+func clearSessionCookie(w http.ResponseWriter, r *http.Request) {
+	if _, err := r.Cookie("session_id"); err != nil {
+		return
+	}
+	cookie := newSessionCookie(r, "")
+	cookie.MaxAge = -1
+	http.SetCookie(w, cookie)
+}
+
+// Récupère l'ID de session depuis les cookies. Un ID qui ne correspond à
+// aucune partie côté serveur est ignoré : la prochaine partie créée recevra un
+// ID neuf, ce qui empêche d'imposer au joueur un ID choisi à l'avance.
 func getSessionID(r *http.Request) string {
 	cookie, err := r.Cookie("session_id")
-	if err != nil {
+	if err != nil || !manager.HasSession(cookie.Value) {
 		return ""
 	}
 	return cookie.Value
 }
 
-// Vérifie si une chaîne contient uniquement des lettres et des espaces
+// Supprime les caractères non imprimables d'un pseudo et vérifie sa longueur
+func sanitizeUsername(username string) (string, error) {
+	username = strings.Map(func(c rune) rune {
+		if !unicode.IsPrint(c) {
+			return -1
+		}
+		return c
+	}, username)
+	username = strings.TrimSpace(username)
+
+	if username == "" {
+		return "", newMsgError("username_required")
+	}
+	if utf8.RuneCountInString(username) > maxUsernameLength {
+		return "", newMsgError("username_too_long", maxUsernameLength)
+	}
+	return username, nil
+}
+
+// Tronque une chaîne au nombre de caractères (runes) donné
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
+
+// Supprime les espaces en début et fin et réduit les espaces multiples à un
+// seul, pour comparer les réponses en plusieurs mots
+func normalizeSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Vérifie si une chaîne contient uniquement des lettres (accentuées comprises),
+// des espaces et des tirets
 func isAlpha(s string) bool {
 	for _, c := range s {
-		if !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && c != ' ' {
+		if !unicode.IsLetter(c) && !isSeparator(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Vérifie qu'une proposition est une lettre seule, ou un mot dont les
+// séparateurs sont isolés entre deux lettres : un espace ou un tiret seul, en
+// tête, en fin ou doublé n'est jamais accepté
+func isValidGuess(guess string) bool {
+	runes := []rune(guess)
+	if len(runes) == 0 {
+		return false
+	}
+	for i, c := range runes {
+		if unicode.IsLetter(c) {
+			continue
+		}
+		if !isSeparator(c) || i == 0 || i == len(runes)-1 || isSeparator(runes[i-1]) {
 			return false
 		}
 	}
 	return true
 }
 
+// Vérifie si un caractère sépare les mots d'une expression (espace ou tiret).
+// Ces caractères sont affichés tels quels et n'ont pas à être devinés.
+func isSeparator(c rune) bool {
+	return c == ' ' || c == '-'
+}
+
+// Compare deux lettres, en confondant formes finales et formes de base (ς et
+// σ...) et en ignorant les accents si fold est activé
+func sameLetter(a, b string, fold bool) bool {
+	if a == b || finalLetterForms.Replace(a) == finalLetterForms.Replace(b) {
+		return true
+	}
+	return fold && accentFolder.Replace(a) == accentFolder.Replace(b)
+}
+
+// Compare une proposition au mot, en ignorant les accents si fold est activé
+func sameWord(guess, word string, fold bool) bool {
+	return sameLetter(guess, word, fold)
+}
+
+// Vérifie si une liste de lettres contient la lettre, en ignorant les accents
+// si fold est activé
+func containsLetter(letters []string, letter string, fold bool) bool {
+	for _, l := range letters {
+		if sameLetter(l, letter, fold) {
+			return true
+		}
+	}
+	return false
+}
+
 // Vérifie si un slice contient un élément spécifique
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -469,10 +2355,15 @@ func contains(slice []string, item string) bool {
 	return false
 }
 
-// Vérifie si toutes les lettres du mot ont été devinées
-func allLettersGuessed(word string, guessed []string) bool {
+// Vérifie si toutes les lettres du mot ont été devinées. Chaque occurrence est
+// comparée aux lettres proposées : une lettre répétée (le « a » de « banane »)
+// n'a besoin d'être proposée qu'une fois.
+func allLettersGuessed(word string, guessed []string, fold bool) bool {
 	for _, c := range word {
-		if !contains(guessed, string(c)) {
+		if isSeparator(c) {
+			continue // Les espaces et tirets n'ont pas à être devinés
+		}
+		if !containsLetter(guessed, string(c), fold) {
 			return false
 		}
 	}
@@ -481,39 +2372,197 @@ func allLettersGuessed(word string, guessed []string) bool {
 
 // Enregistre le score de la partie dans le fichier des scores
 func saveScore(game *Game) {
+	basePoints, blitzBonus := computePoints(game)
 	score := Score{
-		Username:    game.Username,
-		Difficulty:  game.Difficulty,
-		Category:    game.Category,
-		Status:      game.Status,
-		Word:        game.Word,
-		HintsUsed:   game.HintsUsed,
-		Timestamp:   time.Now().Unix(),
+		Version:           scoreVersion,
+		Username:          truncateRunes(game.Username, maxUsernameLength),
+		Avatar:            game.Avatar,
+		Difficulty:        game.Difficulty,
+		Category:          game.Category,
+		SourceCategory:    game.SourceCategory,
+		Status:            game.Status,
+		Word:              game.Word,
+		WordRating:        game.WordRating,
+		WordIndex:         game.WordIndex,
+		HintsUsed:         game.HintsUsed,
+		WrongGuesses:      game.WrongGuesses,
+		Points:            basePoints + blitzBonus,
+		BasePoints:        basePoints,
+		BlitzBonus:        blitzBonus,
+		DurationSeconds:   game.DurationSeconds,
+		Mode:              game.Mode,
+		WordOnly:          game.WordOnlyMode,
+		AccentInsensitive: game.AccentInsensitive,
+		Forfeited:         game.Forfeited,
+		Guesses:           game.Guesses,
+		Timestamp:         time.Now().Unix(),
 	}
 
+	// Une seule écriture à la fois pour ne pas entrelacer les lignes
+	scoresMutex.Lock()
+	defer scoresMutex.Unlock()
+
+	score.ID = generateScoreID()
 	data, err := json.Marshal(score)
 	if err != nil {
-		log.Println("Erreur de marshalling du score:", err)
+		slog.Error("Erreur de marshalling du score", "err", err)
 		return
 	}
 
-	f, err := os.OpenFile(scoreFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	f, err := os.OpenFile(manager.config.ScoresPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		log.Println("Erreur d'ouverture du fichier de scores:", err)
+		slog.Error("Erreur d'ouverture du fichier de scores", "err", err)
 		return
 	}
 	defer f.Close()
 
-	if _, err := f.WriteString(string(data) + "\n"); err != nil {
-		log.Println("Erreur d'écriture dans le fichier de scores:", err)
+	_, err = f.WriteString(string(data) + "\n")
+	// Le score doit être visible dès la prochaine lecture
+	invalidateScoresCache()
+	if err != nil {
+		slog.Error("Erreur d'écriture dans le fichier de scores", "err", err)
+		return
+	}
+	updateRatings(score)
+	game.CurrentStreak = updateStreak(score)
+	if game.AdaptiveLevel != "" {
+		game.AdaptiveLevel = updateProfile(score)
+	}
+	notifyWin(score)
+	slog.Info("Score enregistré",
+		"event", "score_saved",
+		"username", score.Username,
+		"category", score.Category,
+		"difficulty", score.Difficulty,
+		"status", score.Status,
+		"points", score.Points)
+}
+
+// Calcule les points d'une partie : base selon la difficulté, pénalités pour
+// les erreurs et les indices, bonus si la partie a été gagnée rapidement.
+// Le bonus blitz, proportionnel au temps restant, est retourné à part.
+// Une partie perdue ne rapporte aucun point.
+func computePoints(game *Game) (int, int) {
+	if game.Status != "won" {
+		return 0, 0
+	}
+
+	points := pointsByDifficulty[game.Difficulty]
+	points -= game.WrongGuesses * wrongGuessPenalty
+	points -= game.HintsUsed * hintPenalty
+
+	// Bonus de rapidité
+	points += timeBonus(game.DurationSeconds)
+
+	// Une victoire sans aide ni seconde chance vaut davantage
+	if game.Mode == "hardcore" {
+		points *= hardcorePointsMultiplier
+	}
+
+	if points < 0 {
+		points = 0
+	}
+	return points, blitzBonus(game)
+}
+
+// Calcule le bonus blitz d'une victoire : blitzBonusMax si le mot est trouvé
+// immédiatement, jusqu'à 0 à l'heure limite. Une partie sans limite de temps
+// (entraînement) n'y a pas droit.
+func blitzBonus(game *Game) int {
+	if game.Deadline.IsZero() || game.Practice {
+		return 0
+	}
+	allowed := game.Deadline.Sub(game.CreatedAt).Seconds()
+	if allowed <= 0 {
+		return 0
+	}
+	remaining := allowed - float64(game.DurationSeconds)
+	if remaining <= 0 {
+		return 0
+	}
+	return int(float64(blitzBonusMax) * remaining / allowed)
+}
+
+// Calcule le bonus de rapidité selon la durée de la partie en secondes
+func timeBonus(durationSeconds int) int {
+	switch {
+	case durationSeconds < 60:
+		return 10
+	case durationSeconds < 120:
+		return 5
+	}
+	return 0
+}
+
+// Formate une durée en secondes pour l'affichage (ex : « 2 min 05 s »)
+func formatDuration(seconds int) string {
+	if seconds < 60 {
+		return fmt.Sprintf("%d s", seconds)
+	}
+	return fmt.Sprintf("%d min %02d s", seconds/60, seconds%60)
+}
+
+// Nombre d'étapes du dessin du pendu, de 0 (potence vide) à 6 (pendu complet)
+const hangmanStages = 6
+
+// HangmanStage retourne l'étape du dessin du pendu (0 à 6) selon les
+// tentatives restantes, proportionnellement au nombre de tentatives de départ
+// pour que le dessin soit complet quelle que soit la difficulté.
+func HangmanStage(attemptsLeft, maxAttempts int) int {
+	if maxAttempts <= 0 {
+		maxAttempts = hangmanStages
+	}
+	if attemptsLeft < 0 {
+		attemptsLeft = 0
+	}
+	if attemptsLeft > maxAttempts {
+		attemptsLeft = maxAttempts
+	}
+
+	// Arrondi à l'étape la plus proche
+	used := maxAttempts - attemptsLeft
+	return (used*hangmanStages + maxAttempts/2) / maxAttempts
+}
+
+// Parties du pendu dessinées une à une : tête, corps, bras puis jambes
+var hangmanParts = [hangmanStages]string{
+	`<circle cx="150" cy="70" r="20"/>`,
+	`<line x1="150" y1="90" x2="150" y2="150"/>`,
+	`<line x1="150" y1="110" x2="120" y2="130"/>`,
+	`<line x1="150" y1="110" x2="180" y2="130"/>`,
+	`<line x1="150" y1="150" x2="125" y2="190"/>`,
+	`<line x1="150" y1="150" x2="175" y2="190"/>`,
+}
+
+// Retourne le dessin SVG du pendu pour l'étape correspondant aux tentatives
+// restantes, avec une description dans la langue lang. Les bornes sont
+// vérifiées par HangmanStage, et le balisage est entièrement construit ici,
+// description échappée comprise : il peut être marqué comme HTML sûr.
+func hangmanSVG(attemptsLeft, maxAttempts int, lang string) template.HTML {
+	stage := HangmanStage(attemptsLeft, maxAttempts)
+	label := template.HTMLEscapeString(msg(lang, "hangman_stage", stage, hangmanStages))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 220 240" class="hangman-svg" role="img" aria-label="%s">`, label)
+	// Potence, toujours visible
+	b.WriteString(`<g stroke="currentColor" stroke-width="4" stroke-linecap="round" fill="none">`)
+	b.WriteString(`<line x1="20" y1="230" x2="120" y2="230"/>`)
+	b.WriteString(`<line x1="60" y1="230" x2="60" y2="20"/>`)
+	b.WriteString(`<line x1="60" y1="20" x2="150" y2="20"/>`)
+	b.WriteString(`<line x1="150" y1="20" x2="150" y2="50"/>`)
+	for _, part := range hangmanParts[:stage] {
+		b.WriteString(part)
 	}
+	b.WriteString(`</g></svg>`)
+	return template.HTML(b.String())
 }
 
-// Fonction personnalisée pour afficher le mot avec les lettres devinées
-func displayWord(word string, guessed []string) string {
+// Fonction personnalisée pour afficher le mot avec les lettres devinées. Avec
+// fold, une lettre devinée sans accent révèle aussi ses variantes accentuées.
+func displayWord(word string, guessed []string, fold bool) string {
 	display := ""
 	for _, c := range word {
-		if contains(guessed, string(c)) {
+		if isSeparator(c) || containsLetter(guessed, string(c), fold) {
 			display += string(c) + " "
 		} else {
 			display += "_ "
@@ -523,29 +2572,176 @@ func displayWord(word string, guessed []string) string {
 }
 
 // Fournit un indice en révélant une lettre non devinée
-func provideHint(game *Game) {
+func provideHint(game *Game, strategy string) {
+	letter := revealLetter(game, strategy)
+	if letter == "" {
+		return
+	}
+	recordHint(game, letter)
+	game.HintsUsed++
+	game.Message = msg(game.Lang, "hint_letter")
+	game.MessageType = "success"
+}
+
+// Marque comme devinée une lettre restant à trouver, choisie selon la
+// stratégie d'indice, et la retourne. Retourne une chaîne vide si toutes les
+// lettres sont déjà trouvées.
+func revealLetter(game *Game, strategy string) string {
+	// Lettres restant à trouver, sans doublon et dans l'ordre du mot
+	missing := game.MissedLetters()
+	if len(missing) == 0 {
+		return ""
+	}
+
+	letter := missing[0]
+	switch strategy {
+	case "vowel":
+		for _, candidate := range missing {
+			if strings.Contains(vowels, candidate) {
+				letter = candidate
+				break
+			}
+		}
+	case "random":
+		letter = missing[rand.Intn(len(missing))]
+	}
+
+	game.GuessedLetters = append(game.GuessedLetters, letter)
+	return letter
+}
+
+// Fournit un indice de position : choisit au hasard une position dont la
+// lettre n'est pas encore trouvée et l'annonce. La lettre est marquée comme
+// devinée, ce qui révèle aussi ses autres occurrences.
+func providePositionHint(game *Game) {
+	var positions []int
+	runes := []rune(game.Word)
+	for i, c := range runes {
+		if !isSeparator(c) && !game.letterGuessed(string(c)) {
+			positions = append(positions, i)
+		}
+	}
+	if len(positions) == 0 {
+		return
+	}
+
+	pos := positions[rand.Intn(len(positions))]
+	letter := string(runes[pos])
+	game.GuessedLetters = append(game.GuessedLetters, letter)
+	recordHint(game, letter)
+	game.HintsUsed++
+	game.Message = msg(game.Lang, "hint_position", pos+1, letter)
+	game.MessageType = "success"
+}
+
+// Fournit un indice de catégorie : révèle la première lettre du mot et une
+// description textuelle de la catégorie
+func provideCategoryClue(game *Game) {
 	for _, c := range game.Word {
 		letter := string(c)
-		if !contains(game.GuessedLetters, letter) {
+		if !game.letterGuessed(letter) {
 			game.GuessedLetters = append(game.GuessedLetters, letter)
-			game.HintsUsed++
-			game.Message = "Indice : Une lettre a été révélée."
-			game.MessageType = "success"
-			break
+			recordHint(game, letter)
 		}
+		break
+	}
+
+	game.HintClue = categoryDescription(game.Lang, game.Category)
+	game.HintsUsed++
+	game.Message = msg(game.Lang, "hint_category", game.HintClue)
+	game.MessageType = "success"
+}
+
+// Retourne la description textuelle d'une catégorie dans la langue donnée
+func categoryDescription(lang, category string) string {
+	description := msg(lang, "category_"+category)
+	if description == "category_"+category {
+		return msg(lang, "category_unknown")
 	}
+	return description
 }
 
 // Fonction de nettoyage des sessions expirées
 func cleanupSessions() {
 	for {
 		time.Sleep(10 * time.Minute)
-		gamesMutex.Lock()
-		for id, game := range games {
-			if time.Since(game.CreatedAt) > sessionExpiration {
-				delete(games, id)
+		manager.Cleanup()
+
+		roomsMutex.Lock()
+		for code, room := range rooms {
+			if time.Since(room.CreatedAt) > manager.config.SessionTTL {
+				delete(rooms, code)
+			}
+		}
+		roomsMutex.Unlock()
+
+		cleanupRateLimits()
+		cleanupCustomLists()
+		cleanupRecentWords()
+	}
+}
+
+// Sauvegarde les parties en cours dans le fichier d'état
+func persistGames() {
+	data, err := manager.marshalGames()
+	if err != nil {
+		slog.Error("Erreur de marshalling des parties", "err", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(gamesStatePath), 0755); err != nil {
+		slog.Error("Erreur de création du dossier des parties", "err", err)
+		return
+	}
+
+	// Écrire dans un fichier temporaire puis renommer pour éviter un fichier tronqué
+	tmpPath := gamesStatePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		slog.Error("Erreur d'écriture du fichier des parties", "err", err)
+		return
+	}
+	if err := os.Rename(tmpPath, gamesStatePath); err != nil {
+		slog.Error("Erreur de renommage du fichier des parties", "err", err)
+	}
+}
+
+// Sauvegarde périodiquement les parties en cours
+func persistGamesPeriodically() {
+	for {
+		time.Sleep(persistInterval)
+		persistGames()
+		persistAnalytics()
+	}
+}
+
+// Restaure les parties sauvegardées en ignorant celles qui ont expiré
+func loadGames() {
+	data, err := os.ReadFile(gamesStatePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Error("Erreur de lecture du fichier des parties", "err", err)
+		}
+		return
+	}
+
+	var saved map[string]map[string]*Game
+	if err := json.Unmarshal(data, &saved); err != nil {
+		// Ancien format : une seule partie par session
+		var legacy map[string]*Game
+		if legacyErr := json.Unmarshal(data, &legacy); legacyErr != nil {
+			slog.Error("Erreur de parsing du fichier des parties", "err", err)
+			return
+		}
+		saved = make(map[string]map[string]*Game)
+		for sessionID, game := range legacy {
+			if game == nil {
+				continue
 			}
+			game.ID = generateGameID(nil)
+			saved[sessionID] = map[string]*Game{game.ID: game}
 		}
-		gamesMutex.Unlock()
 	}
+
+	count := manager.restoreGames(saved)
+	slog.Info("Parties restaurées", "event", "games_restored", "count", count, "path", gamesStatePath)
 }
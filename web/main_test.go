@@ -0,0 +1,716 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAllLettersGuessed(t *testing.T) {
+	tests := []struct {
+		name    string
+		word    string
+		guessed []string
+		want    bool
+	}{
+		{"banane complet", "banane", []string{"b", "a", "n", "e"}, true},
+		{"banane sans le e", "banane", []string{"b", "a", "n"}, false},
+		{"lettre répétée proposée deux fois", "banane", []string{"a", "a", "b", "n", "e"}, true},
+		{"séparateurs ignorés", "chou-fleur", []string{"c", "h", "o", "u", "f", "l", "e", "r"}, true},
+		{"aucune lettre", "banane", nil, false},
+		{"new york complet", "new york", []string{"n", "e", "w", "y", "o", "r", "k"}, true},
+		{"new york sans le k", "new york", []string{"n", "e", "w", "y", "o", "r"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allLettersGuessed(tt.word, tt.guessed, false); got != tt.want {
+				t.Errorf("allLettersGuessed(%q, %v) = %v, want %v", tt.word, tt.guessed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisplayWord(t *testing.T) {
+	tests := []struct {
+		word    string
+		guessed []string
+		want    string
+	}{
+		{"banane", []string{"a"}, "_ a _ a _ _"},
+		{"new york", nil, "_ _ _   _ _ _ _"},
+		{"new york", []string{"n", "e", "w"}, "n e w   _ _ _ _"},
+		{"new york", []string{"n", "e", "w", "y", "o", "r", "k"}, "n e w   y o r k"},
+		{"chou-fleur", []string{"o"}, "_ _ o _ - _ _ _ _ _"},
+	}
+	for _, tt := range tests {
+		if got := displayWord(tt.word, tt.guessed, false); got != tt.want {
+			t.Errorf("displayWord(%q, %v) = %q, want %q", tt.word, tt.guessed, got, tt.want)
+		}
+	}
+}
+
+func TestApplyGuessRepeatedLetters(t *testing.T) {
+	useTempScores(t)
+
+	tests := []struct {
+		name         string
+		guesses      []string
+		wantStatus   string
+		wantGuessed  int
+		wantWrong    int
+		wantMessage  string
+		wantRevealed string
+	}{
+		{
+			name:         "banane gagné en quatre lettres",
+			guesses:      []string{"b", "a", "n", "e"},
+			wantStatus:   "won",
+			wantGuessed:  4,
+			wantMessage:  "all_letters_guessed",
+			wantRevealed: "b a n a n e",
+		},
+		{
+			name:         "lettre répétée comptée une fois",
+			guesses:      []string{"a"},
+			wantStatus:   "ongoing",
+			wantGuessed:  1,
+			wantMessage:  "correct_guess",
+			wantRevealed: "_ a _ a _ _",
+		},
+		{
+			name:         "lettre déjà proposée",
+			guesses:      []string{"a", "x", "a", "x"},
+			wantStatus:   "ongoing",
+			wantGuessed:  2,
+			wantWrong:    1,
+			wantMessage:  "letter_already_tried",
+			wantRevealed: "_ a _ a _ _",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := newGame("alice", "easy", "random", "", "banane")
+			game.Practice = true
+			for _, guess := range tt.guesses {
+				applyGuess(game, guess)
+			}
+			if game.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", game.Status, tt.wantStatus)
+			}
+			if len(game.GuessedLetters) != tt.wantGuessed {
+				t.Errorf("GuessedLetters = %v, want %d letters", game.GuessedLetters, tt.wantGuessed)
+			}
+			if game.WrongGuesses != tt.wantWrong {
+				t.Errorf("WrongGuesses = %d, want %d", game.WrongGuesses, tt.wantWrong)
+			}
+			if want := msg(game.Lang, tt.wantMessage); game.Message != want {
+				t.Errorf("Message = %q, want %q", game.Message, want)
+			}
+			if got := displayWord(game.Word, game.GuessedLetters, false); got != tt.wantRevealed {
+				t.Errorf("displayWord = %q, want %q", got, tt.wantRevealed)
+			}
+		})
+	}
+}
+
+func TestGameHandlerConcurrentGuesses(t *testing.T) {
+	useTempScores(t)
+	tmpl, err := parseTemplates()
+	if err != nil {
+		t.Fatalf("parseTemplates: %v", err)
+	}
+	templates = tmpl
+
+	sessionID := generateSessionID()
+	game := newGame("alice", "easy", "random", "", "banane")
+	game.Practice = true
+	gameID := manager.NewGame(sessionID, game)
+
+	var wg sync.WaitGroup
+	for _, letter := range []string{"b", "n"} {
+		wg.Add(1)
+		go func(letter string) {
+			defer wg.Done()
+			form := url.Values{"game": {gameID}, "csrf_token": {game.CSRFToken}, "guess": {letter}}
+			req := httptest.NewRequest(http.MethodPost, "/game", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+			rec := httptest.NewRecorder()
+			gameHandler(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("POST %q: status %d", letter, rec.Code)
+			}
+		}(letter)
+	}
+	wg.Wait()
+
+	view := manager.Snapshot(game)
+	guessed := append([]string(nil), view.GuessedLetters...)
+	sort.Strings(guessed)
+	if strings.Join(guessed, ",") != "b,n" {
+		t.Errorf("GuessedLetters = %v, want b and n once each", view.GuessedLetters)
+	}
+	if view.AttemptsLeft != view.MaxAttempts {
+		t.Errorf("AttemptsLeft = %d, want %d", view.AttemptsLeft, view.MaxAttempts)
+	}
+}
+
+func TestSaveScoreConcurrent(t *testing.T) {
+	useTempScores(t)
+
+	const writers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			game := newGame(fmt.Sprintf("player%d", i), "easy", "animals", "", "chat")
+			game.Status = "won"
+			saveScore(game)
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(manager.config.ScoresPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != writers {
+		t.Fatalf("%d lines, want %d", len(lines), writers)
+	}
+	for i, line := range lines {
+		var score Score
+		if err := json.Unmarshal([]byte(line), &score); err != nil {
+			t.Errorf("line %d is not valid JSON: %v", i+1, err)
+		}
+	}
+}
+
+func BenchmarkReadSortedScores(b *testing.B) {
+	useTempScores(b)
+	for i := 0; i < 1000; i++ {
+		game := newGame(fmt.Sprintf("player%d", i%50), "easy", "animals", "", "chat")
+		game.Status = "won"
+		saveScore(game)
+	}
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := readSortedScores("points"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			scoresMutex.Lock()
+			invalidateScoresCache()
+			scoresMutex.Unlock()
+			if _, err := readSortedScores("points"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestReadScoresMigratesOldVersions(t *testing.T) {
+	useTempScores(t)
+	lines := []string{
+		// Version 1 : pas de "v", de mode ni de détail des points
+		`{"username":"alice","difficulty":"easy","category":"animals","status":"won","word":"chat","hints_used":0,"points":50,"blitz_bonus":5,"timestamp":1}`,
+		// Version 2 : pas de nombre de mauvaises propositions
+		`{"v":2,"username":"bob","difficulty":"easy","category":"random","source_category":"animals","status":"lost","word":"chien","hints_used":0,"points":0,"mode":"hardcore","guesses":[{"guess":"a","correct":false,"timestamp":2},{"guess":"c","correct":true,"timestamp":3},{"guess":"z","correct":false,"timestamp":4}],"timestamp":5}`,
+	}
+	if err := os.WriteFile(manager.config.ScoresPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scores, err := readScores()
+	if err != nil {
+		t.Fatalf("readScores: %v", err)
+	}
+	if len(scores) != 2 {
+		t.Fatalf("%d scores, want 2", len(scores))
+	}
+	for _, score := range scores {
+		if score.Version != scoreVersion {
+			t.Errorf("%s: Version = %d, want %d", score.Username, score.Version, scoreVersion)
+		}
+	}
+
+	v1 := scores[0]
+	if v1.Mode != "normal" {
+		t.Errorf("v1 Mode = %q, want normal", v1.Mode)
+	}
+	if v1.SourceCategory != "animals" {
+		t.Errorf("v1 SourceCategory = %q, want animals", v1.SourceCategory)
+	}
+	if v1.BasePoints != 45 {
+		t.Errorf("v1 BasePoints = %d, want 45", v1.BasePoints)
+	}
+	if v1.WordRating != scoreWordDifficulty("chat") {
+		t.Errorf("v1 WordRating = %d, want %d", v1.WordRating, scoreWordDifficulty("chat"))
+	}
+
+	v2 := scores[1]
+	if v2.Mode != "hardcore" || v2.SourceCategory != "animals" {
+		t.Errorf("v2 Mode, SourceCategory = %q, %q, want the values from the line", v2.Mode, v2.SourceCategory)
+	}
+	if v2.WrongGuesses != 2 {
+		t.Errorf("v2 WrongGuesses = %d, want 2", v2.WrongGuesses)
+	}
+}
+
+func TestHangmanSVGStages(t *testing.T) {
+	seen := make(map[string]int)
+	for attemptsLeft := 6; attemptsLeft >= 0; attemptsLeft-- {
+		svg := string(hangmanSVG(attemptsLeft, 6, "fr"))
+		stage := 6 - attemptsLeft
+		if previous, exists := seen[svg]; exists {
+			t.Errorf("stage %d: same SVG as stage %d", stage, previous)
+		}
+		seen[svg] = stage
+
+		decoder := xml.NewDecoder(strings.NewReader(svg))
+		for {
+			_, err := decoder.Token()
+			if err != nil {
+				if err != io.EOF {
+					t.Errorf("stage %d: invalid SVG: %v", stage, err)
+				}
+				break
+			}
+		}
+		if want := fmt.Sprintf(`aria-label="Pendu - étape %d sur %d"`, stage, hangmanStages); !strings.Contains(svg, want) {
+			t.Errorf("stage %d: SVG %q does not contain %s", stage, svg, want)
+		}
+	}
+
+	if svg := string(hangmanSVG(0, 6, "en")); !strings.Contains(svg, `aria-label="Hangman - stage 6 of 6"`) {
+		t.Errorf("English SVG %q has no English label", svg)
+	}
+}
+
+func TestHardGameAttempts(t *testing.T) {
+	useTempScores(t)
+
+	for _, hint := range []bool{false, true} {
+		t.Run(fmt.Sprintf("hint=%v", hint), func(t *testing.T) {
+			game := newGame("alice", "hard", "animals", "", "chat")
+			if game.AttemptsLeft != 4 || game.MaxAttempts != 4 {
+				t.Fatalf("hard game starts with %d/%d attempts, want 4/4", game.AttemptsLeft, game.MaxAttempts)
+			}
+			if hint {
+				// Un indice gratuit ne coûte aucune tentative
+				game.HintPolicy = "free"
+				if !applyHint(game, "") || game.HintsUsed != 1 {
+					t.Fatalf("hint refused: %q", game.Message)
+				}
+			}
+
+			for i, guess := range []string{"x", "y", "z", "w"} {
+				applyGuess(game, guess)
+				wantStatus := "ongoing"
+				if i == 3 {
+					wantStatus = "lost"
+				}
+				if game.Status != wantStatus || game.AttemptsLeft != 3-i {
+					t.Fatalf("after wrong guess %d: status %q with %d attempts, want %q with %d", i+1, game.Status, game.AttemptsLeft, wantStatus, 3-i)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyGuessWordLength(t *testing.T) {
+	for _, guess := range []string{"cha", "chats"} {
+		game := newGame("alice", "easy", "animals", "", "chat")
+		if applyGuess(game, guess) {
+			t.Errorf("guess %q was accepted", guess)
+		}
+		if want := msg(game.Lang, "word_length", 4); game.Message != want {
+			t.Errorf("guess %q: Message = %q, want %q", guess, game.Message, want)
+		}
+		if game.AttemptsLeft != game.MaxAttempts || game.WrongGuesses != 0 {
+			t.Errorf("guess %q: %d/%d attempts and %d wrong guesses, want no attempt lost", guess, game.AttemptsLeft, game.MaxAttempts, game.WrongGuesses)
+		}
+	}
+}
+
+func TestAccentedWord(t *testing.T) {
+	useTempScores(t)
+	game := newGame("alice", "easy", "random", "", "élève")
+	game.Practice = true
+	if !isAlpha(game.Word) {
+		t.Errorf("isAlpha(%q) = false, want true", game.Word)
+	}
+
+	steps := []struct {
+		guess       string
+		wantHas     bool
+		wantDisplay string
+	}{
+		{"é", true, "é _ _ _ _"},
+		{"e", true, "é _ _ _ e"},
+		{"è", true, "é _ è _ e"},
+		{"a", false, "é _ è _ e"},
+	}
+	for _, step := range steps {
+		if got := game.hasLetter(step.guess); got != step.wantHas {
+			t.Errorf("hasLetter(%q) = %v, want %v", step.guess, got, step.wantHas)
+		}
+		if !applyGuess(game, step.guess) {
+			t.Fatalf("guess %q rejected: %q", step.guess, game.Message)
+		}
+		if got := displayWord(game.Word, game.GuessedLetters, false); got != step.wantDisplay {
+			t.Errorf("after %q: displayWord = %q, want %q", step.guess, got, step.wantDisplay)
+		}
+	}
+	if game.WrongGuesses != 1 || game.Status != "ongoing" {
+		t.Errorf("%d wrong guesses, status %q, want 1 and ongoing", game.WrongGuesses, game.Status)
+	}
+}
+
+func TestFilterScoresCategory(t *testing.T) {
+	scores := []Score{
+		{Username: "alice", Category: "animals", Difficulty: "easy"},
+		{Username: "bob", Category: "countries", Difficulty: "easy"},
+		{Username: "carol", Category: "animals", Difficulty: "hard"},
+	}
+
+	filtered := filterScores(scores, scoreFilters{Category: "animals"})
+	if len(filtered) != 2 {
+		t.Fatalf("filterScores(animals) = %+v, want 2 scores", filtered)
+	}
+	for _, score := range filtered {
+		if score.Category != "animals" {
+			t.Errorf("filterScores(animals) returned %+v", score)
+		}
+	}
+
+	if filtered := filterScores(scores, scoreFilters{Category: "planets"}); len(filtered) != 0 {
+		t.Errorf("filterScores(planets) = %+v, want no score", filtered)
+	}
+}
+
+func TestHangmanStage(t *testing.T) {
+	tests := []struct {
+		attemptsLeft, maxAttempts int
+		want                      int
+	}{
+		{6, 6, 0},
+		{3, 6, 3},
+		{0, 6, 6},
+		{8, 8, 0},
+		{4, 8, 3},
+		{0, 8, 6},
+		{4, 4, 0},
+		{2, 4, 3},
+		{0, 4, 6},
+		{1, 3, 4},
+		{-1, 6, 6},
+		{9, 6, 0},
+		{0, 0, 6},
+	}
+	for _, tt := range tests {
+		if got := HangmanStage(tt.attemptsLeft, tt.maxAttempts); got != tt.want {
+			t.Errorf("HangmanStage(%d, %d) = %d, want %d", tt.attemptsLeft, tt.maxAttempts, got, tt.want)
+		}
+	}
+
+	// Quel que soit le nombre de tentatives, le dessin part de la potence vide,
+	// avance sans reculer et finit complet
+	for _, maxAttempts := range []int{1, 3, 4, 6, 8, 10, 12} {
+		previous := 0
+		for attemptsLeft := maxAttempts; attemptsLeft >= 0; attemptsLeft-- {
+			stage := HangmanStage(attemptsLeft, maxAttempts)
+			if stage < previous || stage > hangmanStages {
+				t.Errorf("HangmanStage(%d, %d) = %d after stage %d", attemptsLeft, maxAttempts, stage, previous)
+			}
+			previous = stage
+		}
+		if first := HangmanStage(maxAttempts, maxAttempts); first != 0 {
+			t.Errorf("HangmanStage(%d, %d) = %d, want 0", maxAttempts, maxAttempts, first)
+		}
+		if previous != hangmanStages {
+			t.Errorf("HangmanStage(0, %d) = %d, want %d", maxAttempts, previous, hangmanStages)
+		}
+	}
+}
+
+func TestCorrectAndMissedLetters(t *testing.T) {
+	tests := []struct {
+		word        string
+		guessed     []string
+		wantCorrect []string
+		wantMissed  []string
+	}{
+		{"banane", []string{"n", "x", "a"}, []string{"a", "n"}, []string{"b", "e"}},
+		{"banane", nil, []string{}, []string{"b", "a", "n", "e"}},
+		{"chou-fleur", []string{"u", "c", "h", "o", "f", "l", "e", "r"}, []string{"c", "h", "o", "u", "f", "l", "e", "r"}, []string{}},
+	}
+	for _, tt := range tests {
+		game := newGame("alice", "easy", "random", "", tt.word)
+		game.GuessedLetters = tt.guessed
+		correct, missed := game.CorrectLetters(), game.MissedLetters()
+		if fmt.Sprint(correct) != fmt.Sprint(tt.wantCorrect) || fmt.Sprint(missed) != fmt.Sprint(tt.wantMissed) {
+			t.Errorf("%q with %v: correct %v, missed %v, want %v and %v", tt.word, tt.guessed, correct, missed, tt.wantCorrect, tt.wantMissed)
+		}
+
+		// Chaque lettre du mot est dans une seule des deux listes
+		seen := make(map[string]int)
+		for _, letter := range append(correct, missed...) {
+			seen[letter]++
+		}
+		for _, c := range tt.word {
+			if letter := string(c); !isSeparator(c) && seen[letter] != 1 {
+				t.Errorf("%q with %v: letter %q appears %d times in correct and missed letters", tt.word, tt.guessed, letter, seen[letter])
+			}
+		}
+		if len(seen) != len(correct)+len(missed) {
+			t.Errorf("%q with %v: correct %v and missed %v overlap", tt.word, tt.guessed, correct, missed)
+		}
+	}
+}
+
+func TestGuessWithExtraSpaces(t *testing.T) {
+	for _, guess := range []string{"new  york", " new york ", "new \t york"} {
+		if got := normalizeSpaces(guess); got != "new york" {
+			t.Errorf("normalizeSpaces(%q) = %q, want %q", guess, got, "new york")
+		}
+		if !sameWord(normalizeSpaces(guess), "new york", false) {
+			t.Errorf("sameWord(%q, new york) = false, want true", guess)
+		}
+	}
+	if sameWord("newyork", "new york", false) {
+		t.Error("sameWord(newyork, new york) = true, want false")
+	}
+
+	useTempScores(t)
+	game := newGame("alice", "easy", "countries", "", "new york")
+	game.Practice = true
+	if !applyGuess(game, "new  york") || game.Status != "won" {
+		t.Errorf("guess %q: status %q, message %q, want won", "new  york", game.Status, game.Message)
+	}
+}
+
+func TestWrongGuessesWithHints(t *testing.T) {
+	useTempScores(t)
+	game := newGame("alice", "easy", "random", "", "banane")
+	game.Practice = true
+	game.HintPolicy = "free"
+
+	applyGuess(game, "x")
+	applyGuess(game, "a")
+	if !applyHint(game, "") {
+		t.Fatalf("hint refused: %q", game.Message)
+	}
+	applyGuess(game, "z")
+	applyGuess(game, "x")
+
+	if got := wrongGuesses(game); strings.Join(got, ",") != "x,z" {
+		t.Errorf("wrongGuesses = %v, want [x z]", got)
+	}
+	// Trois lettres proposées et une révélée par l'indice
+	if len(game.GuessedLetters) != 4 {
+		t.Errorf("GuessedLetters = %v, want 4 letters", game.GuessedLetters)
+	}
+	if game.WrongGuesses != 2 {
+		t.Errorf("WrongGuesses = %d, want 2", game.WrongGuesses)
+	}
+}
+
+func TestHintsRemaining(t *testing.T) {
+	useTempScores(t)
+	game := newGame("alice", "easy", "random", "", "banane")
+	game.Practice = true
+	game.HintPolicy = "free"
+	game.MaxHints = 2
+
+	for want := 1; want >= 0; want-- {
+		if !applyHint(game, "") {
+			t.Fatalf("hint refused with %d hints left: %q", want+1, game.Message)
+		}
+		if got := game.HintsRemaining(); got != want {
+			t.Errorf("HintsRemaining = %d after %d hints, want %d", got, game.HintsUsed, want)
+		}
+	}
+
+	// Plus d'indice disponible : la demande est refusée sans rien changer
+	if applyHint(game, "") {
+		t.Error("hint accepted with no hint left")
+	}
+	if game.Message != msg(game.Lang, "max_hints_reached") || game.HintsUsed != 2 || game.HintsRemaining() != 0 {
+		t.Errorf("after a refused hint: message %q, %d used, %d remaining", game.Message, game.HintsUsed, game.HintsRemaining())
+	}
+
+	// Le nombre d'indices peut baisser en cours de partie, jamais le restant sous zéro
+	makeWordOnly(game)
+	if got := game.HintsRemaining(); got != 0 {
+		t.Errorf("HintsRemaining = %d with MaxHints below HintsUsed, want 0", got)
+	}
+}
+
+func TestScoreFiltersPeriod(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	scores := []Score{
+		{Username: "before", Timestamp: from.Add(-time.Second).Unix()},
+		{Username: "start", Timestamp: from.Unix()},
+		{Username: "middle", Timestamp: from.Add(24 * time.Hour).Unix()},
+		{Username: "end", Timestamp: to.Unix()},
+	}
+
+	query := url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}}
+	filters, err := parseScoreFilters(httptest.NewRequest(http.MethodGet, "/scores?"+query.Encode(), nil))
+	if err != nil {
+		t.Fatalf("parseScoreFilters: %v", err)
+	}
+	var names []string
+	for _, score := range filterScores(scores, filters) {
+		names = append(names, score.Username)
+	}
+	if strings.Join(names, ",") != "start,middle" {
+		t.Errorf("scores in [from, to) = %v, want [start middle]", names)
+	}
+
+	for _, query := range []string{"from=2024-01-01", "to=hier", "from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z"} {
+		rec := httptest.NewRecorder()
+		scoresHandler(rec, httptest.NewRequest(http.MethodGet, "/scores?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("/scores?%s: status %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestBlitzBonus(t *testing.T) {
+	timed := func(durationSeconds int) *Game {
+		game := newGame("alice", "easy", "random", "", "banane")
+		game.Deadline = game.CreatedAt.Add(100 * time.Second)
+		game.DurationSeconds = durationSeconds
+		return game
+	}
+
+	if got := blitzBonus(timed(0)); got != blitzBonusMax {
+		t.Errorf("blitzBonus with all the time left = %d, want %d", got, blitzBonusMax)
+	}
+	if got := blitzBonus(timed(1)); got != blitzBonusMax-1 {
+		t.Errorf("blitzBonus with 99 s of 100 left = %d, want %d", got, blitzBonusMax-1)
+	}
+	if got := blitzBonus(timed(99)); got != 0 {
+		t.Errorf("blitzBonus with 1 s of 100 left = %d, want 0", got)
+	}
+	if got := blitzBonus(timed(95)); got != 1 {
+		t.Errorf("blitzBonus with 5 s of 100 left = %d, want 1", got)
+	}
+	if got := blitzBonus(timed(150)); got != 0 {
+		t.Errorf("blitzBonus after the deadline = %d, want 0", got)
+	}
+
+	practice := timed(0)
+	practice.Practice = true
+	if got := blitzBonus(practice); got != 0 {
+		t.Errorf("blitzBonus in practice = %d, want 0", got)
+	}
+	untimed := timed(0)
+	untimed.Deadline = time.Time{}
+	if got := blitzBonus(untimed); got != 0 {
+		t.Errorf("blitzBonus without deadline = %d, want 0", got)
+	}
+}
+
+func TestIsValidGuess(t *testing.T) {
+	tests := []struct {
+		guess string
+		want  bool
+	}{
+		{"a", true},
+		{"é", true},
+		{"new york", true},
+		{"chou-fleur", true},
+		{"", false},
+		{" ", false},
+		{"-", false},
+		{" york", false},
+		{"york ", false},
+		{"new  york", false},
+		{"new -york", false},
+		{"a1", false},
+	}
+	for _, tt := range tests {
+		if got := isValidGuess(tt.guess); got != tt.want {
+			t.Errorf("isValidGuess(%q) = %v, want %v", tt.guess, got, tt.want)
+		}
+	}
+}
+
+func TestAccentInsensitiveGuess(t *testing.T) {
+	if !sameLetter("e", "é", true) || sameLetter("e", "é", false) {
+		t.Error("sameLetter(e, é) must only match when accents are ignored")
+	}
+
+	useTempScores(t)
+	tests := []struct {
+		name        string
+		insensitive bool
+		hardcore    bool
+		wantDisplay string
+		wantWrong   int
+	}{
+		{"insensible aux accents", true, false, "_ _ _ é", 0},
+		{"strict", false, false, "_ _ _ _", 1},
+		{"hardcore reste strict", true, true, "_ _ _ _", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := newGame("alice", "easy", "random", "", "café")
+			game.Practice = true
+			if tt.hardcore {
+				game.Mode = "hardcore"
+			}
+			if tt.insensitive {
+				enableAccentInsensitive(game)
+			}
+			applyGuess(game, "e")
+			if got := displayWord(game.Word, game.GuessedLetters, game.AccentInsensitive); got != tt.wantDisplay {
+				t.Errorf("displayWord = %q, want %q", got, tt.wantDisplay)
+			}
+			if game.WrongGuesses != tt.wantWrong {
+				t.Errorf("WrongGuesses = %d, want %d", game.WrongGuesses, tt.wantWrong)
+			}
+		})
+	}
+}
+
+func TestSortedGuesses(t *testing.T) {
+	game := newGame("alice", "easy", "random", "", "élève")
+	game.GuessedLetters = []string{"v", "é", "z", "a", "e", "l", "è"}
+	played := strings.Join(game.GuessedLetters, ",")
+
+	sorted := sortedGuesses(game)
+	if got := strings.Join(sorted, ","); got != "a,e,è,é,l,v,z" {
+		t.Errorf("sortedGuesses = %v, want [a e è é l v z]", sorted)
+	}
+	if len(sorted) != len(game.GuessedLetters) {
+		t.Errorf("sortedGuesses = %v, want the %d guessed letters", sorted, len(game.GuessedLetters))
+	}
+	for _, letter := range game.GuessedLetters {
+		if !contains(sorted, letter) {
+			t.Errorf("sortedGuesses = %v, missing %q", sorted, letter)
+		}
+	}
+	if got := strings.Join(game.GuessedLetters, ","); got != played {
+		t.Errorf("GuessedLetters = %s after sorting, want %s", got, played)
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"sync"
+)
+
+// GameManager regroupe l'état partagé du jeu : les parties de chaque session
+// et les listes de mots, avec les verrous qui les protègent, et la
+// configuration du serveur. Les handlers utilisent l'instance par défaut
+// manager, créée par main avec la configuration chargée. Un test peut créer
+// la sienne avec newGameManager, y tirer des mots et jouer des parties sans
+// passer par HTTP ni toucher à l'instance par défaut ; les scores des parties
+// terminées vont dans le fichier des scores de l'instance par défaut.
+type GameManager struct {
+	mu    sync.Mutex                  // Protège games et les parties qu'elle contient
+	games map[string]map[string]*Game // Parties en cours, par session puis par ID de partie
+
+	wordsMu     sync.RWMutex                              // Protège words et themedWords
+	words       map[string]map[string][]string            // Mots par catégorie puis difficulté
+	themedWords map[string]map[string]map[string][]string // Mots des sous-listes, par catégorie, difficulté puis thème
+
+	config Config // Réglages du serveur, fixés à la création
+}
+
+// Instance utilisée par les handlers. main la remplace au démarrage par une
+// instance créée avec la configuration et les listes de mots chargées.
+var manager = newGameManager(nil, nil, defaultConfig())
+
+// Crée un gestionnaire sans partie, avec les listes de mots et la
+// configuration données
+func newGameManager(words map[string]map[string][]string, themedWords map[string]map[string]map[string][]string, config Config) *GameManager {
+	return &GameManager{
+		games:       make(map[string]map[string]*Game),
+		words:       words,
+		themedWords: themedWords,
+		config:      config,
+	}
+}
+
+// Remplace d'un coup les listes de mots. Les parties en cours ont déjà leur
+// mot et ne sont pas concernées.
+func (m *GameManager) setWords(words map[string]map[string][]string, themedWords map[string]map[string]map[string][]string) {
+	m.wordsMu.Lock()
+	m.words = words
+	m.themedWords = themedWords
+	m.wordsMu.Unlock()
+}
+
+// NewGame enregistre une nouvelle partie dans la session et retourne son ID
+func (m *GameManager) NewGame(sessionID string, game *Game) string {
+	m.mu.Lock()
+	if m.games[sessionID] == nil {
+		m.games[sessionID] = make(map[string]*Game)
+	}
+	game.ID = generateGameID(m.games[sessionID])
+	game.ResumeCode = m.generateResumeCode()
+	game.WatchCode = m.generateWatchCode()
+	m.games[sessionID][game.ID] = game
+	m.mu.Unlock()
+
+	slog.Info("Partie créée",
+		"event", "game_started",
+		"session_id", sessionID,
+		"game_id", game.ID,
+		"username", game.Username,
+		"category", game.Category,
+		"difficulty", game.Difficulty,
+		"mode", game.Mode)
+	return game.ID
+}
+
+// HasSession indique si la session a au moins une partie côté serveur
+func (m *GameManager) HasSession(sessionID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.games[sessionID]) > 0
+}
+
+// Get retourne la partie demandée d'une session. Sans ID de partie, la partie
+// en cours la plus récemment jouée est choisie, ou à défaut la dernière terminée.
+func (m *GameManager) Get(sessionID, gameID string) (*Game, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessionGames := m.games[sessionID]
+	if gameID != "" {
+		game, exists := sessionGames[gameID]
+		return game, exists
+	}
+
+	var latest *Game
+	for _, game := range sessionGames {
+		if latest == nil {
+			latest = game
+			continue
+		}
+		latestOngoing := latest.Status == "ongoing"
+		ongoing := game.Status == "ongoing"
+		if ongoing != latestOngoing {
+			if ongoing {
+				latest = game
+			}
+			continue
+		}
+		if game.LastActivity.After(latest.LastActivity) {
+			latest = game
+		}
+	}
+	return latest, latest != nil
+}
+
+// Update modifie la partie sous verrou et retourne une copie de son nouvel
+// état, qui peut être lue sans verrou pendant le rendu
+func (m *GameManager) Update(game *Game, apply func(game *Game)) Game {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	apply(game)
+	return game.snapshot()
+}
+
+// Snapshot retourne une copie de l'état actuel de la partie
+func (m *GameManager) Snapshot(game *Game) Game {
+	return m.Update(game, func(*Game) {})
+}
+
+// Active retourne une copie des parties en cours d'une session, de la plus
+// ancienne à la plus récente
+func (m *GameManager) Active(sessionID string) []Game {
+	if sessionID == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	var active []Game
+	for _, game := range m.games[sessionID] {
+		if game.Status == "ongoing" {
+			active = append(active, game.snapshot())
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].CreatedAt.Before(active[j].CreatedAt)
+	})
+	return active
+}
+
+// ForfeitSession abandonne toutes les parties en cours d'une session. Leurs
+// scores sont enregistrés comme des abandons.
+func (m *GameManager) ForfeitSession(sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, game := range m.games[sessionID] {
+		if game.Status == "ongoing" {
+			forfeitGame(game)
+		}
+	}
+}
+
+// ForfeitIdle abandonne d'office les parties chronométrées restées trop
+// longtemps sans proposition (voir checkIdle). Les parties sont marquées sous
+// verrou, mais leurs scores sont enregistrés après, pour ne pas bloquer les
+// autres requêtes pendant les écritures.
+func (m *GameManager) ForfeitIdle() {
+	var idle []*Game
+	m.mu.Lock()
+	for _, sessionGames := range m.games {
+		for _, game := range sessionGames {
+			if idleExpired(game, m.config.IdleTimeout) {
+				markIdleForfeited(game)
+				idle = append(idle, game)
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	for _, game := range idle {
+		// La partie est terminée et ne change plus, sauf les champs que
+		// l'enregistrement du score met à jour, reportés sous verrou
+		view := m.Snapshot(game)
+		finishGame(&view)
+		m.Update(game, func(game *Game) {
+			game.DurationSeconds = view.DurationSeconds
+			game.CurrentStreak = view.CurrentStreak
+			game.AdaptiveLevel = view.AdaptiveLevel
+		})
+	}
+}
+
+// FindByResumeCode retourne l'ID de session et la partie associées au code de
+// reprise, ou nil si aucune partie active ne correspond
+func (m *GameManager) FindByResumeCode(code string) (string, *Game) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.findByResumeCode(code)
+}
+
+// Doit être appelée avec m.mu verrouillé
+func (m *GameManager) findByResumeCode(code string) (string, *Game) {
+	for sessionID, sessionGames := range m.games {
+		for _, game := range sessionGames {
+			if game.ResumeCode == code {
+				return sessionID, game
+			}
+		}
+	}
+	return "", nil
+}
+
+// Génère un code de reprise unique parmi les parties actives.
+// Doit être appelée avec m.mu verrouillé.
+func (m *GameManager) generateResumeCode() string {
+	for {
+		code := generateShortCode(resumeCodeLength)
+		if _, game := m.findByResumeCode(code); game == nil {
+			return code
+		}
+	}
+}
+
+// FindByWatchCode retourne une copie de la partie associée au code
+// spectateur. La copie ne permet pas de modifier la partie.
+func (m *GameManager) FindByWatchCode(code string) (Game, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if game := m.findByWatchCode(code); game != nil {
+		return game.snapshot(), true
+	}
+	return Game{}, false
+}
+
+// Doit être appelée avec m.mu verrouillé
+func (m *GameManager) findByWatchCode(code string) *Game {
+	if code == "" {
+		return nil
+	}
+	for _, sessionGames := range m.games {
+		for _, game := range sessionGames {
+			if game.WatchCode == code {
+				return game
+			}
+		}
+	}
+	return nil
+}
+
+// Génère un code spectateur unique parmi les parties actives.
+// Doit être appelée avec m.mu verrouillé.
+func (m *GameManager) generateWatchCode() string {
+	for {
+		code := generateShortCode(resumeCodeLength)
+		if m.findByWatchCode(code) == nil {
+			return code
+		}
+	}
+}
+
+// FindRoomGame retourne la partie de la session qui participe au salon, ou nil
+func (m *GameManager) FindRoomGame(sessionID, code string) *Game {
+	if sessionID == "" {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, game := range m.games[sessionID] {
+		if game.RoomCode == code {
+			return game
+		}
+	}
+	return nil
+}
+
+// Cleanup supprime les parties expirées et les sessions devenues vides
+func (m *GameManager) Cleanup() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for sessionID, sessionGames := range m.games {
+		for id, game := range sessionGames {
+			if game.Expired(m.config.SessionTTL) {
+				delete(sessionGames, id)
+			}
+		}
+		if len(sessionGames) == 0 {
+			delete(m.games, sessionID)
+		}
+	}
+}
+
+// Encode toutes les parties en JSON pour le fichier d'état
+func (m *GameManager) marshalGames() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return json.Marshal(m.games)
+}
+
+// Ajoute des parties restaurées en ignorant celles qui ont expiré, et retourne
+// le nombre de parties ajoutées
+func (m *GameManager) restoreGames(saved map[string]map[string]*Game) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for sessionID, sessionGames := range saved {
+		for id, game := range sessionGames {
+			if game == nil || game.Expired(m.config.SessionTTL) {
+				continue
+			}
+			// Les parties sauvegardées avant le calcul de la difficulté du mot
+			if game.WordRating == 0 {
+				game.WordRating = scoreWordDifficulty(game.Word)
+			}
+			// ... ou avant l'ajout du mode spectateur
+			if game.WatchCode == "" {
+				game.WatchCode = m.generateWatchCode()
+			}
+			if m.games[sessionID] == nil {
+				m.games[sessionID] = make(map[string]*Game)
+			}
+			m.games[sessionID][id] = game
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Redirige les fichiers des scores et ceux écrits à côté vers un dossier
+// temporaire, rétablis à la fin du test
+func useTempScores(t testing.TB) {
+	t.Helper()
+	dir := t.TempDir()
+	savedScores, savedGames := manager.config.ScoresPath, gamesStatePath
+	manager.config.ScoresPath = filepath.Join(dir, filepath.Base(savedScores))
+	gamesStatePath = filepath.Join(dir, filepath.Base(savedGames))
+	resetScoresCache := func() {
+		scoresMutex.Lock()
+		invalidateScoresCache()
+		scoresMutex.Unlock()
+	}
+	resetScoresCache()
+	t.Cleanup(func() {
+		manager.config.ScoresPath, gamesStatePath = savedScores, savedGames
+		resetScoresCache()
+	})
+}
+
+func TestGameManagerPlaysGame(t *testing.T) {
+	useTempScores(t)
+	m := newGameManager(map[string]map[string][]string{
+		"animals": {"easy": {"chat"}},
+	}, nil, defaultConfig())
+
+	word, _, _, err := m.RandomWord("easy", "animals", "", false)
+	if err != nil {
+		t.Fatalf("RandomWord: %v", err)
+	}
+	if word != "chat" {
+		t.Fatalf("RandomWord = %q, want %q", word, "chat")
+	}
+
+	game := newGame("alice", "easy", "animals", "", word)
+	id := m.NewGame("session", game)
+	if got, ok := m.Get("session", id); !ok || got != game {
+		t.Fatalf("Get(%q) did not return the new game", id)
+	}
+	if _, ok := manager.Get("session", id); ok {
+		t.Fatal("the game leaked into the default manager")
+	}
+
+	for _, guess := range []string{"c", "x", "h", "a", "t"} {
+		m.Update(game, func(game *Game) {
+			applyGuess(game, guess)
+		})
+	}
+	view := m.Snapshot(game)
+	if view.Status != "won" {
+		t.Fatalf("Status = %q, want won", view.Status)
+	}
+	if view.WrongGuesses != 1 {
+		t.Errorf("WrongGuesses = %d, want 1", view.WrongGuesses)
+	}
+
+	scores, err := readScores()
+	if err != nil {
+		t.Fatalf("readScores: %v", err)
+	}
+	if len(scores) != 1 || scores[0].Username != "alice" || scores[0].Word != "chat" {
+		t.Errorf("scores = %+v, want one won game of alice on chat", scores)
+	}
+}
+
+func TestForfeitIdle(t *testing.T) {
+	useTempScores(t)
+	config := defaultConfig()
+	config.IdleTimeout = time.Minute
+	m := newGameManager(nil, nil, config)
+	idle := newGame("alice", "easy", "animals", "", "chat")
+	// Un indice récent ne compte pas comme une proposition
+	idle.LastGuess = time.Now().Add(-2 * time.Minute)
+	idle.LastActivity = time.Now()
+	active := newGame("bob", "easy", "animals", "", "chien")
+	m.NewGame("idle", idle)
+	m.NewGame("active", active)
+
+	m.ForfeitIdle()
+
+	if view := m.Snapshot(idle); view.Status != "lost" || !view.Forfeited {
+		t.Errorf("idle game: Status = %q, Forfeited = %v, want a forfeit", view.Status, view.Forfeited)
+	}
+	if view := m.Snapshot(active); view.Status != "ongoing" {
+		t.Errorf("active game: Status = %q, want ongoing", view.Status)
+	}
+	scores, err := readScores()
+	if err != nil {
+		t.Fatalf("readScores: %v", err)
+	}
+	if len(scores) != 1 || scores[0].Username != "alice" || !scores[0].Forfeited {
+		t.Errorf("scores = %+v, want one forfeit by alice", scores)
+	}
+}
+
+func TestRejectedGuessKeepsIdleTimer(t *testing.T) {
+	useTempScores(t)
+	past := time.Now().Add(-time.Hour)
+	game := newGame("alice", "easy", "animals", "", "chat")
+	game.Practice = true
+	game.LastGuess = past
+
+	// Proposition invalide, mot de mauvaise longueur, lettre en mode mot entier
+	// puis lettre déjà proposée : aucune n'est prise en compte
+	applyGuess(game, " ")
+	applyGuess(game, "chien")
+	game.WordOnlyMode = true
+	applyGuess(game, "c")
+	game.WordOnlyMode = false
+	game.GuessedLetters = []string{"c"}
+	applyGuess(game, "c")
+	if !game.LastGuess.Equal(past) {
+		t.Errorf("LastGuess = %v after rejected guesses, want %v", game.LastGuess, past)
+	}
+
+	applyGuess(game, "h")
+	if !game.LastGuess.After(past) {
+		t.Error("LastGuess was not updated by an accepted guess")
+	}
+}
+
+func TestActivityDelaysExpiration(t *testing.T) {
+	useTempScores(t)
+	config := defaultConfig()
+	config.SessionTTL = 30 * time.Minute
+	m := newGameManager(nil, nil, config)
+
+	hourAgo := time.Now().Add(-time.Hour)
+	idle := newGame("alice", "easy", "animals", "", "chat")
+	active := newGame("alice", "easy", "animals", "", "chien")
+	for _, game := range []*Game{idle, active} {
+		game.Practice = true
+		game.CreatedAt, game.LastActivity = hourAgo, hourAgo
+	}
+	idleID := m.NewGame("session", idle)
+	activeID := m.NewGame("session", active)
+	if !active.Expired(config.SessionTTL) {
+		t.Fatal("game idle for an hour is not expired")
+	}
+
+	form := url.Values{"csrf_token": {active.CSRFToken}, "guess": {"c"}}
+	req := httptest.NewRequest(http.MethodPost, "/game", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	m.Update(active, func(game *Game) {
+		if !playTurn(req, game, "fr") {
+			t.Error("playTurn rejected the CSRF token")
+		}
+	})
+	if active.Expired(config.SessionTTL) {
+		t.Error("game still expired after a guess")
+	}
+
+	m.Cleanup()
+	if _, ok := m.Get("session", activeID); !ok {
+		t.Error("Cleanup removed the active game")
+	}
+	if _, ok := m.Get("session", idleID); ok {
+		t.Error("Cleanup kept the expired game")
+	}
+}
+
+func TestRandomWordEmptyCategory(t *testing.T) {
+	m := newGameManager(map[string]map[string][]string{
+		"animals":   {"easy": {}, "hard": {"chat"}},
+		"countries": {"easy": {}},
+	}, nil, defaultConfig())
+
+	for _, category := range []string{"animals", "countries", "planets", randomCategory} {
+		if word, _, _, err := m.RandomWord("easy", category, "", false); !errors.Is(err, errNoWord) {
+			t.Errorf("RandomWord(easy, %s) = %q, %v, want errNoWord", category, word, err)
+		}
+	}
+	if word, _, _, err := m.RandomWord("hard", "animals", "", false); err != nil || word != "chat" {
+		t.Errorf("RandomWord(hard, animals) = %q, %v, want chat", word, err)
+	}
+}
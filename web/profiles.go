@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Difficulté à choisir pour jouer au niveau adaptatif du joueur
+const autoDifficulty = "auto"
+
+// Niveau adaptatif d'un joueur qui n'a encore terminé aucune partie en "auto"
+var defaultAdaptiveLevel = "medium"
+
+// playerProfile garde le niveau adaptatif d'un joueur : il monte d'un cran
+// après une victoire en "auto" et descend d'un cran après une défaite
+type playerProfile struct {
+	Level string `json:"level"`
+}
+
+var (
+	profilesMutex sync.Mutex // Mutex pour sérialiser l'accès au fichier des profils
+)
+
+// Chemin des profils des joueurs, à côté des scores
+func profilesPath() string {
+	return manager.config.scoresFile("profiles.json")
+}
+
+// Lit les profils depuis le fichier. Un fichier absent donne une map vide.
+// Doit être appelée avec profilesMutex verrouillé.
+func readProfiles() (map[string]*playerProfile, error) {
+	profiles := make(map[string]*playerProfile)
+	data, err := os.ReadFile(profilesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// Écrit les profils dans un fichier temporaire puis le renomme.
+// Doit être appelée avec profilesMutex verrouillé.
+func writeProfiles(profiles map[string]*playerProfile) error {
+	data, err := json.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(profilesPath()), 0755); err != nil {
+		return err
+	}
+	tmpPath := profilesPath() + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, profilesPath())
+}
+
+// Retourne le niveau adaptatif actuel du joueur
+func adaptiveLevel(username string) string {
+	profilesMutex.Lock()
+	defer profilesMutex.Unlock()
+
+	profiles, err := readProfiles()
+	if err != nil {
+		slog.Error("Erreur de lecture des profils", "err", err)
+		return defaultAdaptiveLevel
+	}
+	if profile, exists := profiles[username]; exists && contains(wordDifficulties, profile.Level) {
+		return profile.Level
+	}
+	return defaultAdaptiveLevel
+}
+
+// Remplace la difficulté "auto" par le niveau adaptatif du joueur. Le booléen
+// indique si la partie suit le niveau adaptatif.
+func resolveDifficulty(username, difficulty string) (string, bool) {
+	if difficulty != autoDifficulty {
+		return difficulty, false
+	}
+	return adaptiveLevel(username), true
+}
+
+// Ajuste le niveau adaptatif du joueur après une partie en "auto" : une
+// victoire le fait monter d'un cran, une défaite ou un abandon le fait
+// descendre, sans sortir de wordDifficulties. Retourne le nouveau niveau.
+func updateProfile(score Score) string {
+	profilesMutex.Lock()
+	defer profilesMutex.Unlock()
+
+	profiles, err := readProfiles()
+	if err != nil {
+		slog.Error("Erreur de lecture des profils", "err", err)
+		return score.Difficulty
+	}
+
+	level := 0
+	for i, difficulty := range wordDifficulties {
+		if difficulty == score.Difficulty {
+			level = i
+		}
+	}
+	if score.Status == "won" {
+		level = min(level+1, len(wordDifficulties)-1)
+	} else {
+		level = max(level-1, 0)
+	}
+	profiles[score.Username] = &playerProfile{Level: wordDifficulties[level]}
+
+	if err := writeProfiles(profiles); err != nil {
+		slog.Error("Erreur d'écriture des profils", "err", err)
+	}
+	return wordDifficulties[level]
+}
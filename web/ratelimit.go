@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	gameCreationLimit  = 10          // Nombre maximum de parties créées par IP sur la fenêtre
+	gameCreationWindow = time.Minute // Fenêtre glissante de la limite
+
+	gameCreations      = make(map[string][]time.Time) // Dates des créations récentes par IP
+	gameCreationsMutex sync.Mutex                     // Mutex pour sécuriser l'accès aux créations
+)
+
+// Middleware limitant le nombre de parties créées par IP. Seules les requêtes
+// POST sont comptées, la navigation et le jeu ne sont pas affectés.
+func rateLimitGameCreation(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && !allowGameCreation(clientIP(r), time.Now()) {
+			message := msg(requestLang(w, r), "too_many_games_created")
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				writeAPIError(w, http.StatusTooManyRequests, "too_many_games_created", message)
+				return
+			}
+			http.Error(w, message, http.StatusTooManyRequests)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// Enregistre une création pour l'IP et indique si elle reste sous la limite
+func allowGameCreation(ip string, now time.Time) bool {
+	gameCreationsMutex.Lock()
+	defer gameCreationsMutex.Unlock()
+
+	// Ne garder que les créations encore dans la fenêtre
+	var recent []time.Time
+	for _, t := range gameCreations[ip] {
+		if now.Sub(t) < gameCreationWindow {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= gameCreationLimit {
+		gameCreations[ip] = recent
+		return false
+	}
+	gameCreations[ip] = append(recent, now)
+	return true
+}
+
+// Supprime les IP dont toutes les créations sont sorties de la fenêtre
+func cleanupRateLimits() {
+	gameCreationsMutex.Lock()
+	defer gameCreationsMutex.Unlock()
+	for ip, times := range gameCreations {
+		if len(times) == 0 || time.Since(times[len(times)-1]) >= gameCreationWindow {
+			delete(gameCreations, ip)
+		}
+	}
+}
+
+// Retourne l'IP du client, en tenant compte de X-Forwarded-For si activé
+// (Config.TrustProxy)
+func clientIP(r *http.Request) string {
+	if manager.config.TrustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRateLimitGameCreation(t *testing.T) {
+	created := 0
+	handler := rateLimitGameCreation(func(w http.ResponseWriter, r *http.Request) {
+		created++
+	})
+	post := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < gameCreationLimit; i++ {
+		if code := post("192.0.2.1:1234"); code != http.StatusOK {
+			t.Fatalf("creation %d: status %d, want 200", i+1, code)
+		}
+	}
+	if code := post("192.0.2.1:5678"); code != http.StatusTooManyRequests {
+		t.Errorf("creation %d: status %d, want 429", gameCreationLimit+1, code)
+	}
+	if created != gameCreationLimit {
+		t.Errorf("%d games created, want %d", created, gameCreationLimit)
+	}
+
+	// Une autre IP n'est pas concernée, ni la navigation
+	if code := post("192.0.2.2:1234"); code != http.StatusOK {
+		t.Errorf("other IP: status %d, want 200", code)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET: status %d, want 200", rec.Code)
+	}
+}
+
+func TestClientIPTrustProxy(t *testing.T) {
+	saved := manager.config.TrustProxy
+	t.Cleanup(func() { manager.config.TrustProxy = saved })
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7, 192.0.2.1")
+
+	manager.config.TrustProxy = false
+	if ip := clientIP(req); ip != "192.0.2.1" {
+		t.Errorf("clientIP without TRUST_PROXY = %q, want 192.0.2.1", ip)
+	}
+	manager.config.TrustProxy = true
+	if ip := clientIP(req); ip != "198.51.100.7" {
+		t.Errorf("clientIP with TRUST_PROXY = %q, want 198.51.100.7", ip)
+	}
+
+	cfg, err := loadConfig(func(key string) string {
+		if key == "TRUST_PROXY" {
+			return "true"
+		}
+		return ""
+	})
+	if err != nil || !cfg.TrustProxy {
+		t.Errorf("loadConfig(TRUST_PROXY=true) = %+v, %v, want TrustProxy", cfg, err)
+	}
+	if _, err := loadConfig(func(key string) string {
+		if key == "TRUST_PROXY" {
+			return "peut-être"
+		}
+		return ""
+	}); err == nil {
+		t.Error("loadConfig(TRUST_PROXY=peut-être) succeeded, want error")
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// playerRating est le classement Elo d'un joueur
+type playerRating struct {
+	Rating float64 `json:"rating"`
+	Games  int     `json:"games"`
+}
+
+// rankingEntry est une ligne de la page /ranking
+type rankingEntry struct {
+	Rank     int
+	Username string
+	Rating   int
+	Games    int
+}
+
+const (
+	initialRating = 1200.0 // Classement d'un nouveau joueur
+	ratingK       = 32.0   // Amplitude maximale d'une mise à jour
+)
+
+var (
+	ratingsMutex sync.Mutex // Mutex pour sérialiser l'accès au fichier des classements
+
+	// Classement de référence d'un mot selon sa difficulté : chaque partie est
+	// un « match » du joueur contre le mot
+	wordRatingByDifficulty = map[string]float64{
+		"easy":   1000,
+		"medium": 1200,
+		"hard":   1400,
+	}
+)
+
+// Chemin des classements des joueurs, à côté des scores
+func ratingsPath() string {
+	return manager.config.scoresFile("ratings.json")
+}
+
+// Lit les classements depuis le fichier. Un fichier absent donne une map vide.
+// Doit être appelée avec ratingsMutex verrouillé.
+func readRatings() (map[string]*playerRating, error) {
+	ratings := make(map[string]*playerRating)
+	data, err := os.ReadFile(ratingsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ratings, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &ratings); err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+// Écrit les classements dans un fichier temporaire puis le renomme.
+// Doit être appelée avec ratingsMutex verrouillé.
+func writeRatings(ratings map[string]*playerRating) error {
+	data, err := json.Marshal(ratings)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ratingsPath()), 0755); err != nil {
+		return err
+	}
+	tmpPath := ratingsPath() + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, ratingsPath())
+}
+
+// Met à jour le classement Elo du joueur après une partie terminée. Le mot
+// joue le rôle de l'adversaire, avec un classement fixé par sa difficulté.
+func updateRatings(score Score) {
+	wordRating, ok := wordRatingByDifficulty[score.Difficulty]
+	if !ok {
+		wordRating = initialRating
+	}
+
+	ratingsMutex.Lock()
+	defer ratingsMutex.Unlock()
+
+	ratings, err := readRatings()
+	if err != nil {
+		slog.Error("Erreur de lecture des classements", "err", err)
+		return
+	}
+
+	player, exists := ratings[score.Username]
+	if !exists {
+		player = &playerRating{Rating: initialRating}
+		ratings[score.Username] = player
+	}
+
+	expected := 1 / (1 + math.Pow(10, (wordRating-player.Rating)/400))
+	result := 0.0
+	if score.Status == "won" {
+		result = 1
+	}
+	player.Rating += ratingK * (result - expected)
+	player.Games++
+
+	if err := writeRatings(ratings); err != nil {
+		slog.Error("Erreur d'écriture des classements", "err", err)
+	}
+}
+
+// Handler pour la page du classement des joueurs
+func rankingHandler(w http.ResponseWriter, r *http.Request) {
+	ratingsMutex.Lock()
+	ratings, err := readRatings()
+	ratingsMutex.Unlock()
+	if err != nil {
+		http.Error(w, "Impossible de lire les classements.", http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]rankingEntry, 0, len(ratings))
+	for username, player := range ratings {
+		entries = append(entries, rankingEntry{
+			Username: username,
+			Rating:   int(math.Round(player.Rating)),
+			Games:    player.Games,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Rating != entries[j].Rating {
+			return entries[i].Rating > entries[j].Rating
+		}
+		return entries[i].Username < entries[j].Username
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	err = renderTemplate(w, "ranking.html", entries)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
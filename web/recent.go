@@ -0,0 +1,77 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Les derniers mots servis à chaque joueur sont gardés en mémoire pour éviter
+// de lui redonner un mot qu'il vient de jouer. Ils sont perdus au redémarrage
+// et oubliés après recentWordsTTL sans nouvelle partie.
+var (
+	recentWordsTries = 10                               // Tirages maximum pour trouver un mot non récent
+	recentWordsTTL   = 24 * time.Hour                   // Durée après laquelle les mots d'un joueur inactif sont oubliés
+	recentWords      = make(map[string]*recentWordList) // Derniers mots servis, par pseudo
+	recentWordsMutex sync.Mutex                         // Mutex pour sécuriser l'accès à recentWords
+)
+
+// recentWordList garde les derniers mots servis à un joueur
+type recentWordList struct {
+	Words    []string  // Du plus ancien au plus récent
+	LastUsed time.Time // Date du dernier mot servi
+}
+
+// Tire un mot comme GameManager.RandomWord en évitant les size derniers mots
+// servis au joueur (Config.RecentWords, 0 pour désactiver). Si la liste est
+// trop petite pour y échapper, le dernier tirage est gardé même s'il est récent.
+func getFreshWord(username, difficulty, category, listID string, biased bool, size int) (string, string, string, error) {
+	word, source, theme, err := manager.RandomWord(difficulty, category, listID, biased)
+	if err != nil || size <= 0 {
+		return word, source, theme, err
+	}
+
+	recentWordsMutex.Lock()
+	var recent []string
+	if list, exists := recentWords[username]; exists {
+		recent = append(recent, list.Words...)
+	}
+	recentWordsMutex.Unlock()
+
+	for i := 1; i < recentWordsTries && contains(recent, word); i++ {
+		word, source, theme, err = manager.RandomWord(difficulty, category, listID, biased)
+		if err != nil {
+			return word, source, theme, err
+		}
+	}
+	rememberWord(username, word, size)
+	return word, source, theme, nil
+}
+
+// Ajoute un mot aux derniers mots servis au joueur, en oubliant les plus
+// anciens au-delà de size
+func rememberWord(username, word string, size int) {
+	recentWordsMutex.Lock()
+	defer recentWordsMutex.Unlock()
+
+	list, exists := recentWords[username]
+	if !exists {
+		list = &recentWordList{}
+		recentWords[username] = list
+	}
+	list.Words = append(list.Words, word)
+	if len(list.Words) > size {
+		list.Words = list.Words[len(list.Words)-size:]
+	}
+	list.LastUsed = time.Now()
+}
+
+// Oublie les derniers mots des joueurs sans nouvelle partie depuis recentWordsTTL
+func cleanupRecentWords() {
+	recentWordsMutex.Lock()
+	defer recentWordsMutex.Unlock()
+	for username, list := range recentWords {
+		if time.Since(list.LastUsed) > recentWordsTTL {
+			delete(recentWords, username)
+		}
+	}
+}
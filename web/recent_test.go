@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCleanupRecentWords(t *testing.T) {
+	rememberWord("recent-alice", "chat", 5)
+	rememberWord("recent-bob", "chien", 5)
+	recentWordsMutex.Lock()
+	recentWords["recent-bob"].LastUsed = time.Now().Add(-recentWordsTTL - time.Minute)
+	recentWordsMutex.Unlock()
+
+	cleanupRecentWords()
+
+	recentWordsMutex.Lock()
+	defer recentWordsMutex.Unlock()
+	if _, exists := recentWords["recent-alice"]; !exists {
+		t.Error("the words of an active player were forgotten")
+	}
+	if _, exists := recentWords["recent-bob"]; exists {
+		t.Error("the words of an inactive player are still kept")
+	}
+	delete(recentWords, "recent-alice")
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// GuessEvent est une action du joueur enregistrée pour rejouer la partie
+type GuessEvent struct {
+	Guess     string `json:"guess"`                    // Lettre ou mot proposé (ou révélé par un indice)
+	Correct   bool   `json:"correct"`                  // La proposition était juste
+	Hint      bool   `json:"hint,omitempty"`           // La lettre a été révélée par un indice
+	Undo      bool   `json:"undo,omitempty"`           // La lettre a été annulée
+	Freeze    int    `json:"freeze_seconds,omitempty"` // Secondes ajoutées au chrono par un gel qui a révélé la lettre
+	Timestamp int64  `json:"timestamp"`
+}
+
+// replayFrame est une étape de la partie telle qu'affichée par /replay
+type replayFrame struct {
+	Step         int
+	Event        GuessEvent
+	Masked       string
+	WrongGuesses int
+}
+
+// Ajoute une action à l'historique de la partie
+func recordGuess(game *Game, guess string, correct bool) {
+	game.Guesses = append(game.Guesses, GuessEvent{
+		Guess:     guess,
+		Correct:   correct,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// Ajoute une lettre révélée par un indice à l'historique
+func recordHint(game *Game, letter string) {
+	game.Guesses = append(game.Guesses, GuessEvent{
+		Guess:     letter,
+		Correct:   true,
+		Hint:      true,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// Ajoute à l'historique la lettre révélée par un gel du chrono, avec le
+// temps ajouté
+func recordFreeze(game *Game, letter string, seconds int) {
+	game.Guesses = append(game.Guesses, GuessEvent{
+		Guess:     letter,
+		Correct:   true,
+		Hint:      true,
+		Freeze:    seconds,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// Ajoute l'annulation d'une lettre à l'historique
+func recordUndo(game *Game, letter string) {
+	game.Guesses = append(game.Guesses, GuessEvent{
+		Guess:     letter,
+		Correct:   game.hasLetter(letter),
+		Undo:      true,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// Compte les mauvaises propositions d'un historique. Une mauvaise lettre
+// annulée ne compte plus.
+func countWrongGuesses(guesses []GuessEvent) int {
+	wrong := 0
+	for _, event := range guesses {
+		switch {
+		case event.Correct:
+		case event.Undo:
+			wrong--
+		default:
+			wrong++
+		}
+	}
+	return wrong
+}
+
+// Reconstruit l'état du mot après chaque action de l'historique
+func buildReplayFrames(score Score) []replayFrame {
+	var guessed []string
+	wrong := 0
+	frames := make([]replayFrame, 0, len(score.Guesses))
+
+	for i, event := range score.Guesses {
+		switch {
+		case event.Undo:
+			for j := len(guessed) - 1; j >= 0; j-- {
+				if guessed[j] == event.Guess {
+					guessed = append(guessed[:j], guessed[j+1:]...)
+					break
+				}
+			}
+			if !event.Correct {
+				wrong--
+			}
+		case utf8.RuneCountInString(event.Guess) == 1:
+			guessed = append(guessed, event.Guess)
+			if !event.Correct {
+				wrong++
+			}
+		default:
+			// Un mot juste révèle toutes les lettres
+			if event.Correct {
+				for _, c := range score.Word {
+					guessed = append(guessed, string(c))
+				}
+			} else {
+				wrong++
+			}
+		}
+
+		frames = append(frames, replayFrame{
+			Step:         i + 1,
+			Event:        event,
+			Masked:       displayWord(score.Word, guessed, score.AccentInsensitive),
+			WrongGuesses: wrong,
+		})
+	}
+	return frames
+}
+
+// Handler pour revoir le déroulement d'une partie terminée
+func replayHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(r.URL.Query().Get("id"))
+	if id == "" {
+		http.Error(w, "Identifiant de partie manquant.", http.StatusBadRequest)
+		return
+	}
+
+	score, found, err := findScore(id)
+	if err != nil {
+		http.Error(w, "Impossible de lire les scores.", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "Partie introuvable.", http.StatusNotFound)
+		return
+	}
+
+	data := struct {
+		Score  Score
+		Start  string
+		Frames []replayFrame
+	}{
+		Score:  score,
+		Start:  displayWord(score.Word, nil, false),
+		Frames: buildReplayFrames(score),
+	}
+	err = renderTemplate(w, "replay.html", data)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Room représente un salon où deux joueurs s'affrontent sur le même mot
+type Room struct {
+	Code       string
+	Difficulty string
+	Category   string
+	Source     string // Catégorie d'origine du mot
+	WordTheme  string // Thème de la sous-liste d'où vient le mot, "" pour la liste principale
+	Word       string
+	Games      []*Game // Une partie par joueur, au plus roomSize
+	Winner     string  // Pseudo du premier joueur ayant trouvé le mot
+	CreatedAt  time.Time
+}
+
+const (
+	roomSize       = 2 // Nombre de joueurs par salon
+	roomCodeLength = 6 // Longueur du code de salon
+)
+
+var (
+	rooms      = make(map[string]*Room) // Map pour stocker les salons de course
+	roomsMutex sync.Mutex               // Mutex pour sécuriser l'accès aux salons
+)
+
+// Handler pour créer un salon de course
+func createRoomHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+
+	if r.Method == http.MethodPost {
+		username := strings.TrimSpace(r.FormValue("username"))
+		difficulty := r.FormValue("difficulty")
+		category := r.FormValue("category")
+		theme := r.FormValue("theme")
+
+		if username == "" || difficulty == "" || category == "" || theme == "" {
+			http.Error(w, msg(lang, "required_fields"), http.StatusBadRequest)
+			return
+		}
+
+		username, err := sanitizeUsername(username)
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusBadRequest)
+			return
+		}
+
+		word, source, wordTheme, err := manager.RandomWord(difficulty, category, "", false)
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusInternalServerError)
+			return
+		}
+
+		room := &Room{
+			Difficulty: difficulty,
+			Category:   category,
+			Source:     source,
+			WordTheme:  wordTheme,
+			Word:       word,
+			CreatedAt:  time.Now(),
+		}
+
+		roomsMutex.Lock()
+		room.Code = generateRoomCode()
+		rooms[room.Code] = room
+		roomsMutex.Unlock()
+
+		sessionID := getSessionID(r)
+		if sessionID == "" {
+			sessionID = generateSessionID()
+		}
+		if _, err := joinRoom(room, sessionID, username, theme, lang); err != nil {
+			http.Error(w, errorText(lang, err), http.StatusConflict)
+			return
+		}
+		setSessionCookie(w, r, sessionID)
+
+		http.Redirect(w, r, "/room/"+room.Code, http.StatusSeeOther)
+		return
+	}
+
+	// Afficher le formulaire de création de salon
+	err := renderTemplate(w, "room.html", nil)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
+
+// Handler pour consulter ou rejoindre un salon via son code
+func roomHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	code := strings.ToUpper(strings.TrimPrefix(r.URL.Path, "/room/"))
+
+	// Formulaire « Rejoindre » : rediriger vers l'URL du salon
+	if code == "" {
+		code = strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("code")))
+		if code == "" {
+			http.Redirect(w, r, "/room", http.StatusSeeOther)
+			return
+		}
+		http.Redirect(w, r, "/room/"+code, http.StatusSeeOther)
+		return
+	}
+
+	roomsMutex.Lock()
+	room, exists := rooms[code]
+	roomsMutex.Unlock()
+
+	if !exists {
+		http.Error(w, msg(lang, "room_not_found"), http.StatusNotFound)
+		return
+	}
+
+	// Vérifier si le visiteur participe déjà à ce salon
+	sessionID := getSessionID(r)
+	memberGame := manager.FindRoomGame(sessionID, room.Code)
+
+	if r.Method == http.MethodPost {
+		if memberGame != nil {
+			http.Redirect(w, r, gameURL("/game", memberGame), http.StatusSeeOther)
+			return
+		}
+
+		username := strings.TrimSpace(r.FormValue("username"))
+		theme := r.FormValue("theme")
+		if username == "" || theme == "" {
+			http.Error(w, msg(lang, "required_fields"), http.StatusBadRequest)
+			return
+		}
+
+		username, err := sanitizeUsername(username)
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusBadRequest)
+			return
+		}
+
+		if sessionID == "" {
+			sessionID = generateSessionID()
+		}
+		game, err := joinRoom(room, sessionID, username, theme, lang)
+		if err != nil {
+			http.Error(w, errorText(lang, err), http.StatusConflict)
+			return
+		}
+		setSessionCookie(w, r, sessionID)
+
+		http.Redirect(w, r, gameURL("/game", game), http.StatusSeeOther)
+		return
+	}
+
+	// Les parties des joueurs sont copiées sous verrou avant le rendu
+	manager.mu.Lock()
+	roomsMutex.Lock()
+	data := struct {
+		Room       *Room
+		Games      []Game
+		MemberGame *Game
+		Full       bool
+	}{
+		Room:       room,
+		MemberGame: memberGame,
+		Full:       len(room.Games) >= roomSize || room.Winner != "",
+	}
+	for _, game := range room.Games {
+		data.Games = append(data.Games, game.snapshot())
+	}
+	roomsMutex.Unlock()
+	manager.mu.Unlock()
+
+	// Afficher la page du salon
+	err := renderTemplate(w, "room.html", data)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
+
+// Erreur retournée quand un joueur rejoint un salon complet ou déjà gagné
+var errRoomFull = newMsgError("room_full")
+
+// Ajoute un joueur au salon avec sa propre partie, enregistrée dans sa session.
+// La place est vérifiée et prise sous le même verrou, pour que deux joueurs
+// qui rejoignent en même temps ne dépassent pas roomSize.
+func joinRoom(room *Room, sessionID, username, theme, lang string) (*Game, error) {
+	game := newGame(username, room.Difficulty, room.Category, theme, room.Word)
+	game.Mode = "race"
+	game.SourceCategory = room.Source
+	game.WordTheme = room.WordTheme
+	game.WordIndex = manager.wordIndex(room.Source, room.Difficulty, game.WordTheme, room.Word)
+	game.Lang = lang
+	game.RoomCode = room.Code
+
+	roomsMutex.Lock()
+	if len(room.Games) >= roomSize || room.Winner != "" {
+		roomsMutex.Unlock()
+		return nil, errRoomFull
+	}
+	room.Games = append(room.Games, game)
+	roomsMutex.Unlock()
+
+	manager.NewGame(sessionID, game)
+	return game, nil
+}
+
+// Termine le salon lorsqu'un joueur trouve le mot en premier. L'adversaire
+// peut continuer sa partie en solo jusqu'à épuisement de ses tentatives.
+func finishRoom(winner *Game) {
+	roomsMutex.Lock()
+	defer roomsMutex.Unlock()
+
+	room, exists := rooms[winner.RoomCode]
+	if !exists || room.Winner != "" {
+		return
+	}
+	room.Winner = winner.Username
+
+	for _, game := range room.Games {
+		if game != winner && game.Status == "ongoing" {
+			game.Message = msg(game.Lang, "race_lost", winner.Username)
+			game.MessageType = "error"
+		}
+	}
+}
+
+// Génère un code de salon court et facile à saisir, unique parmi les salons actifs.
+// Doit être appelée avec roomsMutex verrouillé.
+func generateRoomCode() string {
+	for {
+		code := generateShortCode(roomCodeLength)
+		if _, exists := rooms[code]; !exists {
+			return code
+		}
+	}
+}
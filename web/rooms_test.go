@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestJoinRoomConcurrent(t *testing.T) {
+	room := &Room{Code: "TSTJON", Difficulty: "easy", Category: "animals", Source: "animals", Word: "chat"}
+
+	const players = 10
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	joined := 0
+	for i := 0; i < players; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			session := fmt.Sprintf("room-session-%d", i)
+			game, err := joinRoom(room, session, fmt.Sprintf("player%d", i), "light", defaultLang)
+			if err != nil {
+				if err != errRoomFull {
+					t.Errorf("joinRoom: %v", err)
+				}
+				return
+			}
+			if game.RoomCode != room.Code {
+				t.Errorf("RoomCode = %q, want %q", game.RoomCode, room.Code)
+			}
+			mu.Lock()
+			joined++
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	if joined != roomSize {
+		t.Errorf("%d players joined, want %d", joined, roomSize)
+	}
+	if len(room.Games) != roomSize {
+		t.Errorf("room has %d games, want %d", len(room.Games), roomSize)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Alphabets du clavier à l'écran, par écriture. Les formes finales (ς, ך,
+// ם...) n'y figurent pas : elles se devinent avec leur forme de base.
+var scriptAlphabets = map[string]string{
+	"latin":  "abcdefghijklmnopqrstuvwxyz",
+	"greek":  "αβγδεζηθικλμνξοπρστυφχψω",
+	"hebrew": "אבגדהוזחטיכלמנסעפצקרשת",
+	"arabic": "ابتثجحخدذرزسشصضطظعغفقكلمنهوي",
+}
+
+var (
+	defaultScript = "latin"                      // Écriture supposée quand rien ne permet de la reconnaître
+	rtlScripts    = []string{"hebrew", "arabic"} // Écritures qui se lisent de droite à gauche
+)
+
+// Tables Unicode utilisées pour reconnaître l'écriture d'un mot
+var scriptTables = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"greek", unicode.Greek},
+	{"hebrew", unicode.Hebrew},
+	{"arabic", unicode.Arabic},
+}
+
+// Ramène les formes finales des lettres à leur forme de base, pour qu'une
+// même lettre soit trouvée quelle que soit sa place dans le mot
+var finalLetterForms = strings.NewReplacer(
+	"ς", "σ",
+	"ך", "כ", "ם", "מ", "ן", "נ", "ף", "פ", "ץ", "צ",
+)
+
+// Retourne l'écriture d'un mot, celle de sa première lettre. Les listes
+// mélangeant les écritures sont ainsi gérées mot par mot.
+func wordScript(word string) string {
+	for _, c := range word {
+		if !unicode.IsLetter(c) {
+			continue
+		}
+		for _, script := range scriptTables {
+			if unicode.Is(script.table, c) {
+				return script.name
+			}
+		}
+		break
+	}
+	return defaultScript
+}
+
+// Script retourne l'écriture du mot de la partie ("latin", "greek"...)
+func (g *Game) Script() string {
+	return wordScript(g.Word)
+}
+
+// TextDir retourne le sens d'écriture du mot pour l'attribut HTML dir :
+// "rtl" pour l'hébreu et l'arabe, "ltr" sinon
+func (g *Game) TextDir() string {
+	if contains(rtlScripts, g.Script()) {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// Retourne l'alphabet du clavier à l'écran pour une écriture, l'alphabet
+// latin si elle est inconnue
+func scriptAlphabet(script string) string {
+	if alphabet, ok := scriptAlphabets[script]; ok {
+		return alphabet
+	}
+	return scriptAlphabets[defaultScript]
+}
@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestWordScript(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"chat", "latin"},
+		{"σεισμος", "greek"},
+		{"שלום", "hebrew"},
+		{"سلام", "arabic"},
+		{"-σεισμος", "greek"},
+		{"", defaultScript},
+	}
+	for _, tt := range tests {
+		if got := wordScript(tt.word); got != tt.want {
+			t.Errorf("wordScript(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestGreekGame(t *testing.T) {
+	useTempScores(t)
+	game := newGame("alice", "easy", "random", "", "σεισμος")
+	game.Practice = true
+
+	if dir := game.TextDir(); dir != "ltr" {
+		t.Errorf("TextDir = %q, want ltr", dir)
+	}
+	states := letterStates(game)
+	if len(states) != len([]rune(scriptAlphabets["greek"])) {
+		t.Errorf("keyboard has %d keys, want the %d Greek letters", len(states), len([]rune(scriptAlphabets["greek"])))
+	}
+	for _, letter := range []string{"a", "ς"} {
+		if _, ok := states[letter]; ok {
+			t.Errorf("keyboard has a %q key", letter)
+		}
+	}
+
+	// Le σ révèle aussi le ς final
+	applyGuess(game, "σ")
+	if got := displayWord(game.Word, game.GuessedLetters, false); got != "σ _ _ σ _ _ ς" {
+		t.Errorf("displayWord = %q, want the final sigma revealed", got)
+	}
+	if state := letterStates(game)["σ"]; state != "correct" {
+		t.Errorf("σ key is %q, want correct", state)
+	}
+	if game.WrongGuesses != 0 {
+		t.Errorf("WrongGuesses = %d, want 0", game.WrongGuesses)
+	}
+
+	// Le ς final proposé seul est déjà essayé
+	applyGuess(game, "ς")
+	if want := msg(game.Lang, "letter_already_tried"); game.Message != want {
+		t.Errorf("Message after ς = %q, want %q", game.Message, want)
+	}
+
+	for _, letter := range []string{"ε", "ι", "μ", "ο"} {
+		applyGuess(game, letter)
+	}
+	if game.Status != "won" {
+		t.Errorf("Status = %q, want won", game.Status)
+	}
+}
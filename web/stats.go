@@ -0,0 +1,126 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// categoryStat représente le nombre de parties et de victoires pour une catégorie ou une difficulté
+type categoryStat struct {
+	Name  string
+	Games int
+	Wins  int
+}
+
+// playerStats regroupe les statistiques d'un joueur ou de l'ensemble des joueurs
+type playerStats struct {
+	Username         string
+	Players          int // Nombre de joueurs distincts (résumé global)
+	Games            int
+	Wins             int
+	Losses           int
+	WinRate          float64 // Pourcentage de victoires
+	AverageHints     float64
+	FavoriteCategory string
+	ByCategory       []categoryStat
+	ByDifficulty     []categoryStat
+	Streak           playerStreak // Série de victoires (statistiques d'un joueur)
+}
+
+// Handler pour la page des statistiques d'un joueur ou globales
+func statsHandler(w http.ResponseWriter, r *http.Request) {
+	scores, err := readScores()
+	if err != nil {
+		http.Error(w, "Impossible de lire les scores.", http.StatusInternalServerError)
+		return
+	}
+
+	// Filtrer les scores du joueur demandé
+	username := strings.TrimSpace(r.URL.Query().Get("username"))
+	if username != "" {
+		var userScores []Score
+		for _, score := range scores {
+			if score.Username == username {
+				userScores = append(userScores, score)
+			}
+		}
+		scores = userScores
+	}
+
+	stats := computeStats(scores)
+	stats.Username = username
+	if username != "" {
+		stats.Streak = getStreak(username)
+	}
+
+	err = renderTemplate(w, "stats.html", stats)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
+
+// Calcule les statistiques agrégées d'une liste de scores
+func computeStats(scores []Score) playerStats {
+	var stats playerStats
+	players := make(map[string]bool)
+	byCategory := make(map[string]*categoryStat)
+	byDifficulty := make(map[string]*categoryStat)
+	totalHints := 0
+
+	for _, score := range scores {
+		stats.Games++
+		won := score.Status == "won"
+		if won {
+			stats.Wins++
+		} else {
+			stats.Losses++
+		}
+		totalHints += score.HintsUsed
+		players[score.Username] = true
+		addCategoryStat(byCategory, score.Category, won)
+		addCategoryStat(byDifficulty, score.Difficulty, won)
+	}
+
+	stats.Players = len(players)
+	if stats.Games > 0 {
+		stats.WinRate = float64(stats.Wins) * 100 / float64(stats.Games)
+		stats.AverageHints = float64(totalHints) / float64(stats.Games)
+	}
+	stats.ByCategory = sortedCategoryStats(byCategory)
+	stats.ByDifficulty = sortedCategoryStats(byDifficulty)
+
+	// La catégorie favorite est la plus jouée
+	for _, c := range stats.ByCategory {
+		if stats.FavoriteCategory == "" || c.Games > byCategory[stats.FavoriteCategory].Games {
+			stats.FavoriteCategory = c.Name
+		}
+	}
+
+	return stats
+}
+
+// Ajoute une partie à la ventilation donnée
+func addCategoryStat(stats map[string]*categoryStat, name string, won bool) {
+	stat, exists := stats[name]
+	if !exists {
+		stat = &categoryStat{Name: name}
+		stats[name] = stat
+	}
+	stat.Games++
+	if won {
+		stat.Wins++
+	}
+}
+
+// Retourne la ventilation triée par nom
+func sortedCategoryStats(stats map[string]*categoryStat) []categoryStat {
+	result := make([]categoryStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// playerStreak est la série de victoires consécutives d'un joueur
+type playerStreak struct {
+	Current int `json:"current"`
+	Best    int `json:"best"`
+}
+
+var (
+	streaksMutex sync.Mutex // Mutex pour sérialiser l'accès au fichier des séries
+)
+
+// Chemin des séries des joueurs, à côté des scores
+func streaksPath() string {
+	return manager.config.scoresFile("streaks.json")
+}
+
+// Lit les séries depuis le fichier. Un fichier absent donne une map vide.
+// Doit être appelée avec streaksMutex verrouillé.
+func readStreaks() (map[string]*playerStreak, error) {
+	streaks := make(map[string]*playerStreak)
+	data, err := os.ReadFile(streaksPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return streaks, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &streaks); err != nil {
+		return nil, err
+	}
+	return streaks, nil
+}
+
+// Écrit les séries dans un fichier temporaire puis le renomme.
+// Doit être appelée avec streaksMutex verrouillé.
+func writeStreaks(streaks map[string]*playerStreak) error {
+	data, err := json.Marshal(streaks)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(streaksPath()), 0755); err != nil {
+		return err
+	}
+	tmpPath := streaksPath() + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, streaksPath())
+}
+
+// Met à jour la série du joueur après une partie terminée : une victoire
+// l'allonge, une défaite ou un abandon la remet à zéro. Retourne la série en cours.
+func updateStreak(score Score) int {
+	streaksMutex.Lock()
+	defer streaksMutex.Unlock()
+
+	streaks, err := readStreaks()
+	if err != nil {
+		slog.Error("Erreur de lecture des séries", "err", err)
+		return 0
+	}
+
+	streak, exists := streaks[score.Username]
+	if !exists {
+		streak = &playerStreak{}
+		streaks[score.Username] = streak
+	}
+
+	if score.Status == "won" {
+		streak.Current++
+		if streak.Current > streak.Best {
+			streak.Best = streak.Current
+		}
+	} else {
+		streak.Current = 0
+	}
+
+	if err := writeStreaks(streaks); err != nil {
+		slog.Error("Erreur d'écriture des séries", "err", err)
+	}
+	return streak.Current
+}
+
+// Retourne la série d'un joueur, vide s'il n'a encore joué aucune partie
+func getStreak(username string) playerStreak {
+	streaksMutex.Lock()
+	defer streaksMutex.Unlock()
+
+	streaks, err := readStreaks()
+	if err != nil {
+		slog.Error("Erreur de lecture des séries", "err", err)
+		return playerStreak{}
+	}
+	if streak, exists := streaks[username]; exists {
+		return *streak
+	}
+	return playerStreak{}
+}
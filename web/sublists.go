@@ -0,0 +1,139 @@
+package main
+
+import (
+	"log/slog"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Les sous-listes thématiques complètent la liste principale d'une catégorie
+// et d'une difficulté : animals_hard_mammals.txt ajoute le thème "mammals" à
+// animals_hard.txt. Leurs mots sortent moins souvent que ceux de la liste
+// principale, selon themedWordWeight.
+var (
+	mainWordWeight   = 2 // Poids d'un mot de la liste principale
+	themedWordWeight = 1 // Poids d'un mot d'une sous-liste thématique
+)
+
+// Charge toutes les sous-listes thématiques du dossier de mots dir. Les
+// fichiers sans sous-liste donnent une map vide, comme avant leur introduction.
+func loadThemedWords(dir string, fold bool) map[string]map[string]map[string][]string {
+	themed := make(map[string]map[string]map[string][]string)
+
+	for _, category := range wordCategories {
+		themed[category] = make(map[string]map[string][]string)
+		for _, difficulty := range wordDifficulties {
+			prefix := category + "_" + difficulty + "_"
+			paths, err := filepath.Glob(filepath.Join(dir, prefix+"*.txt"))
+			if err != nil {
+				slog.Error("Erreur de recherche des sous-listes", "category", category, "difficulty", difficulty, "err", err)
+				continue
+			}
+			for _, path := range paths {
+				theme := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), ".txt")
+				data, err := os.ReadFile(path)
+				if err != nil {
+					slog.Error("Erreur de lecture de la sous-liste", "path", path, "err", err)
+					continue
+				}
+				words := parseWordList(data, fold)
+				if theme == "" || len(words) == 0 {
+					continue
+				}
+				if themed[category][difficulty] == nil {
+					themed[category][difficulty] = make(map[string][]string)
+				}
+				themed[category][difficulty][theme] = words
+				slog.Debug("Sous-liste chargée", "path", path, "theme", theme, "count", len(words))
+			}
+		}
+	}
+
+	return themed
+}
+
+// wordPool réunit les mots candidats à un tirage, avec pour chacun la
+// catégorie et le thème de la liste d'où il vient et le poids de cette liste
+type wordPool struct {
+	words   []string
+	sources []string
+	themes  []string // "" pour la liste principale
+	weights []int
+}
+
+// Ajoute au tirage la liste principale et les sous-listes thématiques d'une
+// catégorie et d'une difficulté.
+// Doit être appelée avec m.wordsMu verrouillé en lecture.
+func (m *GameManager) addToPool(pool *wordPool, category, difficulty string) {
+	pool.add(category, "", m.words[category][difficulty], mainWordWeight)
+	themes := m.themedWords[category][difficulty]
+	names := make([]string, 0, len(themes))
+	for theme := range themes {
+		names = append(names, theme)
+	}
+	sort.Strings(names)
+	for _, theme := range names {
+		pool.add(category, theme, themes[theme], themedWordWeight)
+	}
+}
+
+// Ajoute les mots d'une liste au tirage
+func (p *wordPool) add(source, theme string, words []string, weight int) {
+	for _, word := range words {
+		p.words = append(p.words, word)
+		p.sources = append(p.sources, source)
+		p.themes = append(p.themes, theme)
+		p.weights = append(p.weights, weight)
+	}
+}
+
+// Tire un mot en pondérant chaque mot par le poids de sa liste, ou par sa
+// difficulté calculée si biased est vrai (voir pickWeightedIndex). Retourne
+// le mot avec sa catégorie et son thème d'origine, ou errNoWord si le tirage
+// est vide.
+func (p *wordPool) pick(difficulty string, biased bool) (string, string, string, error) {
+	if len(p.words) == 0 {
+		return "", "", "", errNoWord
+	}
+
+	i := len(p.words) - 1
+	if biased {
+		i = pickWeightedIndex(p.words, difficulty)
+	} else {
+		total := 0
+		for _, weight := range p.weights {
+			total += weight
+		}
+		n := rand.Intn(total)
+		for j, weight := range p.weights {
+			if n < weight {
+				i = j
+				break
+			}
+			n -= weight
+		}
+	}
+	return p.words[i], p.sources[i], p.themes[i], nil
+}
+
+// Retourne la position du mot, à partir de 1, dans la liste d'où il a été
+// tiré : la sous-liste de son thème s'il en a un, sinon la liste principale.
+// Retourne 0 si le mot n'y figure pas, par exemple pour une liste personnalisée.
+func (m *GameManager) wordIndex(category, difficulty, theme, word string) int {
+	m.wordsMu.RLock()
+	defer m.wordsMu.RUnlock()
+
+	words := m.words[category][difficulty]
+	if theme != "" {
+		words = m.themedWords[category][difficulty][theme]
+	}
+	for i, candidate := range words {
+		if candidate == word {
+			return i + 1
+		}
+	}
+	return 0
+}
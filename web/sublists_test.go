@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestRandomWordTheme(t *testing.T) {
+	// "lion" figure dans la liste principale et dans deux thèmes : le thème
+	// retourné doit être celui de la liste tirée
+	m := newGameManager(map[string]map[string][]string{
+		"animals":    {"hard": {"lion"}},
+		"technology": {"hard": {}},
+	}, map[string]map[string]map[string][]string{
+		"animals":    {"hard": {"felins": {"lion", "tigre"}, "savane": {"lion", "zebre"}}},
+		"technology": {"hard": {"reseau": {"routeur"}}},
+	}, defaultConfig())
+	lists := map[string]map[string][]string{
+		"animals":    {"": {"lion"}, "felins": {"lion", "tigre"}, "savane": {"lion", "zebre"}},
+		"technology": {"reseau": {"routeur"}},
+	}
+
+	for _, category := range []string{"animals", randomCategory} {
+		seen := make(map[string]bool)
+		for i := 0; i < 500; i++ {
+			for _, biased := range []bool{false, true} {
+				word, source, theme, err := m.RandomWord("hard", category, "", biased)
+				if err != nil {
+					t.Fatalf("RandomWord(%s): %v", category, err)
+				}
+				if !contains(lists[source][theme], word) {
+					t.Fatalf("RandomWord(%s) = %q from %s/%q, which does not contain it", category, word, source, theme)
+				}
+				seen[source+"/"+theme] = true
+			}
+		}
+		if !seen["animals/felins"] || !seen["animals/savane"] || !seen["animals/"] {
+			t.Errorf("RandomWord(%s) drew from %v, want every animals list", category, seen)
+		}
+		if category == randomCategory && !seen["technology/reseau"] {
+			t.Errorf("mixed draws never used the technology sublist: %v", seen)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Templates et fichiers statiques embarqués dans le binaire, pour qu'il
+// fonctionne sans les dossiers templates/ et static/ à côté de lui
+//
+//go:embed templates/*.html static
+var uiFS embed.FS
+
+var (
+	templates    *template.Template // Templates des pages, analysés au démarrage par main
+	templatesDir = "templates"      // Dossier des templates HTML lu en développement
+	staticDir    = "static"         // Dossier des fichiers statiques servi en développement
+
+	// Fonctions disponibles dans les templates
+	templateFuncs = template.FuncMap{
+		"displayWord": displayWord,
+		"title":       strings.Title, // Fonction pour capitaliser la première lettre
+		"timeFormat": func(timestamp int64) string {
+			t := time.Unix(timestamp, 0)
+			return t.Format("02/01/2006 15:04:05")
+		},
+		"formatDuration": formatDuration,
+		"hangmanStage":   HangmanStage,
+		"hangmanSVG":     hangmanSVG,
+		"letterStates":   letterStates,
+		"wrongGuesses":   wrongGuesses,
+		"sortedGuesses":  sortedGuesses,
+		"wordDifficulty": wordDifficultyLabel,
+		"avatar":         avatarEmoji,
+	}
+)
+
+// Retourne le sous-dossier embedded des fichiers embarqués, ou le dossier
+// diskDir du disque en développement (Config.Dev)
+func uiDir(diskDir, embedded string) fs.FS {
+	if manager.config.Dev {
+		return os.DirFS(diskDir)
+	}
+	sub, err := fs.Sub(uiFS, embedded)
+	if err != nil {
+		// Impossible : les deux dossiers sont embarqués à la compilation
+		panic(err)
+	}
+	return sub
+}
+
+// Analyse les templates un fichier à la fois, pour que l'erreur indique le
+// fichier en cause
+func parseTemplates() (*template.Template, error) {
+	templatesFS := uiDir(templatesDir, "templates")
+	paths, err := fs.Glob(templatesFS, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("aucun template trouvé dans %s", templatesDir)
+	}
+
+	tmpl := template.New("").Funcs(templateFuncs)
+	for _, path := range paths {
+		if _, err := tmpl.ParseFS(templatesFS, path); err != nil {
+			return nil, fmt.Errorf("%s : %w", filepath.Join(templatesDir, path), err)
+		}
+	}
+	return tmpl, nil
+}
+
+// Handler des fichiers statiques, embarqués ou lus sur le disque en développement
+func staticHandler() http.Handler {
+	return http.StripPrefix("/static/", http.FileServer(http.FS(uiDir(staticDir, "static"))))
+}
+
+// Affiche un template. En développement, les templates sont relus à chaque
+// appel pour que les modifications soient visibles sans redémarrer.
+func renderTemplate(w io.Writer, name string, data interface{}) error {
+	tmpl := templates
+	if manager.config.Dev {
+		parsed, err := parseTemplates()
+		if err != nil {
+			slog.Error("Template invalide", "err", err)
+			return err
+		}
+		tmpl = parsed
+	}
+	if tmpl == nil {
+		return errors.New("templates non chargés")
+	}
+	return tmpl.ExecuteTemplate(w, name, data)
+}
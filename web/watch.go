@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Handler de la vue spectateur : GET /watch/{code} affiche le plateau masqué
+// d'une partie, sans session ni formulaire. Le code spectateur ne donne aucun
+// droit sur la partie, contrairement au code de reprise, et les lettres non
+// devinées ne sont jamais affichées, même une fois la partie terminée.
+func watchHandler(w http.ResponseWriter, r *http.Request) {
+	lang := requestLang(w, r)
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, msg(lang, "method_not_allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/watch/")))
+	game, exists := manager.FindByWatchCode(code)
+	if !exists {
+		http.Error(w, msg(lang, "game_not_found"), http.StatusNotFound)
+		return
+	}
+
+	err := renderTemplate(w, "watch.html", &game)
+	if err != nil {
+		http.Error(w, "Erreur lors du rendu de la page.", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+var (
+	webhookTimeout    = 5 * time.Second // Délai maximum d'un appel au webhook
+	webhookRetryDelay = 2 * time.Second // Attente avant l'unique nouvel essai
+	webhookClient     = &http.Client{Timeout: webhookTimeout}
+)
+
+// webhookPayload est le corps JSON envoyé au webhook lors d'une victoire
+type webhookPayload struct {
+	Username   string `json:"username"`
+	Word       string `json:"word"`
+	Category   string `json:"category"`
+	Difficulty string `json:"difficulty"`
+	HintsUsed  int    `json:"hints_used"`
+	Points     int    `json:"points"`
+}
+
+// Annonce une victoire au webhook configuré (Config.WebhookURL), sans bloquer
+// la partie : l'envoi se fait dans une goroutine et n'est retenté qu'une fois
+// en cas d'échec
+func notifyWin(score Score) {
+	url := manager.config.WebhookURL
+	if url == "" || score.Status != "won" {
+		return
+	}
+
+	payload := webhookPayload{
+		Username:   score.Username,
+		Word:       score.Word,
+		Category:   score.Category,
+		Difficulty: score.Difficulty,
+		HintsUsed:  score.HintsUsed,
+		Points:     score.Points,
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		slog.Error("Erreur de marshalling du webhook", "err", err)
+		return
+	}
+
+	go func() {
+		err := postWebhook(url, data)
+		if err == nil {
+			return
+		}
+		slog.Warn("Échec de l'appel au webhook, nouvel essai", "err", err)
+		time.Sleep(webhookRetryDelay)
+		if err := postWebhook(url, data); err != nil {
+			slog.Error("Échec de l'appel au webhook", "username", score.Username, "err", err)
+		}
+	}()
+}
+
+// Envoie le corps JSON au webhook. Une réponse hors 2xx est une erreur.
+func postWebhook(url string, data []byte) error {
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("statut HTTP %d", resp.StatusCode)
+	}
+	return nil
+}